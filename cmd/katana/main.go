@@ -123,7 +123,10 @@ func main() {
 
 }
 
-const defaultBodyReadSize = 4 * 1024 * 1024
+const (
+	defaultBodyReadSize    = 4 * 1024 * 1024
+	defaultDocumentMaxSize = 10 * 1024 * 1024
+)
 
 func readFlags() (*goflags.FlagSet, error) {
 	flagSet := goflags.NewFlagSet()
@@ -141,12 +144,22 @@ pipelines offering both headless and non-headless crawling.`)
 		flagSet.IntVarP(&options.MaxDepth, "depth", "d", 3, "maximum depth to crawl"),
 		flagSet.BoolVarP(&options.ScrapeJSResponses, "js-crawl", "jc", false, "enable endpoint parsing / crawling in javascript file"),
 		flagSet.BoolVarP(&options.ScrapeJSLuiceResponses, "jsluice", "jsl", false, "enable jsluice parsing in javascript file (memory intensive)"),
+		flagSet.BoolVarP(&options.OpenAPI, "openapi", "oa", false, "enable expansion of openapi/swagger specifications and swagger ui pages into api requests"),
+		flagSet.BoolVarP(&options.GraphQL, "graphql", "gql", false, "enable detection of graphql endpoints and enumeration of their queries and mutations"),
+		flagSet.BoolVarP(&options.GraphQLQueries, "graphql-queries", "gqq", false, "request the read-only queries of detected graphql endpoints (mutations are never requested)"),
+		flagSet.BoolVarP(&options.SourceMaps, "source-maps", "sm", false, "enable following and endpoint parsing of source maps of javascript and css files"),
+		flagSet.BoolVarP(&options.DocumentCrawl, "document-crawl", "dc", false, "enable link and metadata parsing in pdf and office documents (pdf,docx,xlsx,pptx)"),
+		flagSet.IntVarP(&options.DocumentMaxSize, "document-max-size", "dms", defaultDocumentMaxSize, "maximum document size to read for parsing"),
+		flagSet.StringSliceVarP(&options.Parsers, "parsers", "ps", nil, "only run the response parsers with the given names, including optional ones (a-href,htmx,jsluice-file,etc)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.DisabledParsers, "disable-parsers", "dps", nil, "response parsers to disable by name (a-href,htmx,jsluice-file,etc)", goflags.CommaSeparatedStringSliceOptions),
 		flagSet.DurationVarP(&options.CrawlDuration, "crawl-duration", "ct", 0, "maximum duration to crawl the target for (s, m, h, d) (default s)"),
-		flagSet.EnumVarP(&options.KnownFiles, "known-files", "kf", goflags.EnumVariable(0), "enable crawling of known files (all,robotstxt,sitemapxml), a minimum depth of 3 is required to ensure all known files are properly crawled.", goflags.AllowdTypes{
+		flagSet.EnumVarP(&options.KnownFiles, "known-files", "kf", goflags.EnumVariable(0), "enable crawling of known files (all,robotstxt,sitemapxml,openapi,graphql), a minimum depth of 3 is required to ensure all known files are properly crawled.", goflags.AllowdTypes{
 			"":           goflags.EnumVariable(0),
 			"all":        goflags.EnumVariable(1),
 			"robotstxt":  goflags.EnumVariable(2),
 			"sitemapxml": goflags.EnumVariable(3),
+			"openapi":    goflags.EnumVariable(4),
+			"graphql":    goflags.EnumVariable(5),
 		}),
 		flagSet.IntVarP(&options.BodyReadSize, "max-response-size", "mrs", defaultBodyReadSize, "maximum response size to read"),
 		flagSet.IntVar(&options.Timeout, "timeout", 10, "time to wait for request in seconds"),
@@ -165,6 +178,7 @@ pipelines offering both headless and non-headless crawling.`)
 		flagSet.BoolVarP(&options.TlsImpersonate, "tls-impersonate", "tlsi", false, "enable experimental client hello (ja3) tls randomization"),
 		flagSet.BoolVarP(&options.DisableRedirects, "disable-redirects", "dr", false, "disable following redirects (default false)"),
 		flagSet.BoolVarP(&options.PathClimb, "path-climb", "pc", false, "enable path climb (auto crawl parent paths)"),
+		flagSet.StringVarP(&options.LoginFlow, "login-flow", "lf", "", "path to yaml login flow executed in the browser before crawling"),
 	)
 
 	flagSet.CreateGroup("debug", "Debug",
@@ -182,8 +196,29 @@ pipelines offering both headless and non-headless crawling.`)
 		flagSet.StringVarP(&options.ChromeDataDir, "chrome-data-dir", "cdd", "", "path to store chrome browser data"),
 		flagSet.StringVarP(&options.SystemChromePath, "system-chrome-path", "scp", "", "use specified chrome browser for headless crawling"),
 		flagSet.BoolVarP(&options.HeadlessNoIncognito, "no-incognito", "noi", false, "start headless chrome without incognito mode"),
-		flagSet.StringVarP(&options.ChromeWSUrl, "chrome-ws-url", "cwu", "", "use chrome browser instance launched elsewhere with the debugger listening at this URL"),
+		flagSet.StringVarP(&options.ChromeWSUrl, "chrome-ws-url", "cwu", "", "use chrome browser instance launched elsewhere with the debugger listening at this URL (comma separated for a pool)"),
+		flagSet.IntVarP(&options.HeadlessInstances, "headless-instances", "hi", 1, "number of local chrome instances to use for headless crawling"),
+		flagSet.IntVarP(&options.HeadlessInstanceConcurrency, "headless-instance-concurrency", "hic", 0, "maximum number of concurrent pages per chrome instance (0 for no limit)"),
 		flagSet.BoolVarP(&options.XhrExtraction, "xhr-extraction", "xhr", false, "extract xhr request url,method in jsonl output"),
+		flagSet.BoolVarP(&options.XhrReplay, "xhr-replay", "xr", false, "replay xhr requests captured in headless mode with their method and body to crawl their responses"),
+		flagSet.BoolVarP(&options.XhrReplayUnsafe, "xhr-replay-unsafe", "xru", false, "also replay captured xhr requests with state changing methods (post,put,patch,delete)"),
+		flagSet.StringSliceVarP(&options.HeadlessBlockResources, "headless-block-resource", "hbr", nil, "resource types to block in headless mode (image,font,media,stylesheet,script,xhr,fetch,websocket,manifest,ping,other)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.HeadlessBlockURLs, "headless-block-url", "hbu", nil, "regex or list of regex of urls to block in headless mode (cli, file)", goflags.FileStringSliceOptions),
+		flagSet.BoolVarP(&options.HeadlessBlockThirdParty, "headless-block-third-party", "hbtp", false, "block requests to other registrable domains than the page in headless mode"),
+		flagSet.BoolVarP(&options.HeadlessRecordBlocked, "headless-record-blocked", "hrb", false, "record blocked headless requests as discovered urls"),
+		flagSet.BoolVarP(&options.ConsoleCapture, "console-capture", "cc", false, "capture browser console messages, js exceptions and failed resource loads in jsonl output"),
+		flagSet.BoolVarP(&options.StorageExtraction, "storage-extraction", "stx", false, "extract web storage, indexeddb names and javascript set cookies in jsonl output"),
+		flagSet.IntVarP(&options.HeadlessMaxRestarts, "headless-max-restarts", "hmr", 3, "maximum number of browser restarts after a crash or disconnection (0 to disable)"),
+		flagSet.StringVarP(&options.HeadlessProfile, "headless-profile", "hp", "", "device, locale and stealth profile to emulate in headless mode (desktop,macos,mobile,iphone or yaml file)"),
+		flagSet.StringVarP(&options.HeadlessWait, "headless-wait", "hw", "", "page readiness strategy in headless mode (stable[:duration],network-idle[:inflight],selector:<css>,js:<expr>,dom-quiet[:duration],timeout:<duration>, ';' separated)"),
+		flagSet.StringSliceVarP(&options.HeadlessWaitRules, "headless-wait-rule", "hwr", nil, "per url page readiness strategy as '<url-regex> => <strategy>' (cli, file)", goflags.FileStringSliceOptions),
+		flagSet.StringVarP(&options.HeadlessDialog, "headless-dialog", "hd", "accept", "response to javascript dialogs in headless mode (accept,dismiss,accept:<prompt text>)"),
+		flagSet.BoolVarP(&options.HeadlessConsent, "headless-consent", "hcs", false, "click through cookie consent banners in headless mode"),
+		flagSet.StringVarP(&options.HeadlessConsentSelectors, "headless-consent-selectors", "hcsf", "", "file with css selectors of consent buttons replacing the default ones"),
+		flagSet.IntVarP(&options.HeadlessScroll, "headless-scroll", "hsc", 0, "maximum number of scrolls to load lazy loaded content in headless mode"),
+		flagSet.BoolVarP(&options.HeadlessOnly, "headless-only", "hlo", false, "render all the requests in the browser, including static resources"),
+		flagSet.StringSliceVarP(&options.HeadlessRoutes, "headless-route", "hrt", nil, "engine of the matching urls as '<url-regex> => headless|standard' (cli, file)", goflags.FileStringSliceOptions),
+		flagSet.StringSliceVarP(&options.HeadlessInitScripts, "headless-init-script", "his", nil, "javascript file to evaluate on every page before page scripts (window.katanaReport sends data to output)", goflags.CommaSeparatedStringSliceOptions),
 	)
 
 	flagSet.CreateGroup("scope", "Scope",
@@ -226,6 +261,7 @@ pipelines offering both headless and non-headless crawling.`)
 		flagSet.StringVarP(&options.OutputTemplate, "output-template", "ot", "", "custom output template"),
 		flagSet.BoolVarP(&options.StoreResponse, "store-response", "sr", false, "store http requests/responses"),
 		flagSet.StringVarP(&options.StoreResponseDir, "store-response-dir", "srd", "", "store http requests/responses to custom directory"),
+		flagSet.BoolVarP(&options.StoreSourceMaps, "store-source-maps", "ssm", false, "store original sources recovered from source maps to the response directory"),
 		flagSet.BoolVarP(&options.NoClobber, "no-clobber", "ncb", false, "do not overwrite output file"),
 		flagSet.StringVarP(&options.StoreFieldDir, "store-field-dir", "sfd", "", "store per-host field to custom directory"),
 		flagSet.BoolVarP(&options.OmitRaw, "omit-raw", "or", false, "omit raw requests/responses from jsonl output"),
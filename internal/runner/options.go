@@ -5,10 +5,12 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/gologger/formatter"
+	"github.com/projectdiscovery/katana/pkg/engine/parser"
 	"github.com/projectdiscovery/katana/pkg/types"
 	"github.com/projectdiscovery/katana/pkg/utils"
 	"github.com/projectdiscovery/utils/errkit"
@@ -25,14 +27,8 @@ func validateOptions(options *types.Options) error {
 		return errkit.New("no inputs specified for crawler")
 	}
 
-	// Disabling automatic form fill (-aff) for headless navigation due to incorrect implementation.
-	// Form filling should be handled via headless actions within the page context
-	if options.Headless && options.AutomaticFormFill {
-		options.AutomaticFormFill = false
-		gologger.Info().Msgf("Automatic form fill (-aff) has been disabled for headless navigation.")
-	}
-
-	if (options.HeadlessOptionalArguments != nil || options.HeadlessNoSandbox || options.SystemChromePath != "") && !options.Headless {
+	// the login flow runs in a browser even when headless crawling is disabled
+	if (options.HeadlessOptionalArguments != nil || options.HeadlessNoSandbox || options.SystemChromePath != "") && !options.Headless && options.LoginFlow == "" {
 		return errkit.New("headless mode (-hl) is required if -ho, -nos or -scp are set")
 	}
 	if options.SystemChromePath != "" {
@@ -40,6 +36,83 @@ func validateOptions(options *types.Options) error {
 			return errkit.New("specified system chrome binary does not exist")
 		}
 	}
+	if options.LoginFlow != "" && !fileutil.FileExists(options.LoginFlow) {
+		return errkit.New("specified login flow file does not exist")
+	}
+	if len(options.HeadlessInitScripts) > 0 && !options.Headless {
+		return errkit.New("headless mode (-hl) is required if -his is set")
+	}
+	if (len(options.HeadlessBlockResources) > 0 || len(options.HeadlessBlockURLs) > 0 || options.HeadlessBlockThirdParty || options.HeadlessRecordBlocked) && !options.Headless {
+		return errkit.New("headless mode (-hl) is required if -hbr, -hbu, -hbtp or -hrb are set")
+	}
+	if (options.ConsoleCapture || options.StorageExtraction || options.HeadlessProfile != "") && !options.Headless {
+		return errkit.New("headless mode (-hl) is required if -cc, -stx or -hp are set")
+	}
+	if (options.HeadlessWait != "" || len(options.HeadlessWaitRules) > 0) && !options.Headless {
+		return errkit.New("headless mode (-hl) is required if -hw or -hwr are set")
+	}
+	if (options.HeadlessConsent || options.HeadlessScroll > 0) && !options.Headless {
+		return errkit.New("headless mode (-hl) is required if -hcs or -hsc are set")
+	}
+	if options.HeadlessConsentSelectors != "" {
+		if !options.HeadlessConsent {
+			return errkit.New("headless consent (-hcs) is required if -hcsf is set")
+		}
+		if !fileutil.FileExists(options.HeadlessConsentSelectors) {
+			return errkit.New("specified consent selectors file does not exist")
+		}
+	}
+	if options.DocumentCrawl && options.DocumentMaxSize <= 0 {
+		return errkit.New("document max size (-dms) must be positive if -dc is set")
+	}
+	if options.XhrReplay && !options.Headless {
+		return errkit.New("headless mode (-hl) is required if -xr is set")
+	}
+	if options.XhrReplayUnsafe && !options.XhrReplay {
+		return errkit.New("xhr replay (-xr) is required if -xru is set")
+	}
+	if (options.HeadlessOnly || len(options.HeadlessRoutes) > 0) && !options.Headless {
+		return errkit.New("headless mode (-hl) is required if -hlo or -hrt are set")
+	}
+	if options.HeadlessOnly && len(options.HeadlessRoutes) > 0 {
+		return errkit.New("headless routes (-hrt) can't be used with -hlo")
+	}
+	if options.HeadlessScroll < 0 {
+		return errkit.New("headless scroll (-hsc) can't be negative")
+	}
+	if options.HeadlessInstances < 1 {
+		return errkit.New("headless instances (-hi) must be at least 1")
+	}
+	if options.HeadlessInstances > 1 && options.ChromeWSUrl != "" {
+		return errkit.New("headless instances (-hi) can't be used with -cwu, specify comma separated urls instead")
+	}
+	if options.HeadlessInstanceConcurrency < 0 {
+		return errkit.New("headless instance concurrency (-hic) can't be negative")
+	}
+	if options.HeadlessMaxRestarts < 0 {
+		return errkit.New("headless max restarts (-hmr) can't be negative")
+	}
+	for _, script := range options.HeadlessInitScripts {
+		if !fileutil.FileExists(script) {
+			return errkit.Newf("specified headless init script %s does not exist", script)
+		}
+	}
+	if options.GraphQLQueries && !options.GraphQL {
+		return errkit.New("graphql (-gql) is required if -gqq is set")
+	}
+	if options.KnownFiles == "openapi" && !options.OpenAPI {
+		return errkit.New("openapi (-oa) is required if -kf openapi is set")
+	}
+	if options.KnownFiles == "graphql" && !options.GraphQL {
+		return errkit.New("graphql (-gql) is required if -kf graphql is set")
+	}
+	if options.StoreSourceMaps && !options.SourceMaps {
+		return errkit.New("source maps (-sm) is required if -ssm is set")
+	}
+	if options.StoreSourceMaps && !options.StoreResponse {
+		gologger.Debug().Msgf("store source maps specified, enabling \"sr\" flag automatically\n")
+		options.StoreResponse = true
+	}
 	if options.StoreResponseDir != "" && !options.StoreResponse {
 		gologger.Debug().Msgf("store response directory specified, enabling \"sr\" flag automatically\n")
 		options.StoreResponse = true
@@ -58,6 +131,12 @@ func validateOptions(options *types.Options) error {
 		}
 		options.FilterRegex = append(options.FilterRegex, cr)
 	}
+	parserNames := parser.Names()
+	for _, name := range slices.Concat(options.Parsers, options.DisabledParsers) {
+		if !slices.Contains(parserNames, name) {
+			return errkit.Newf("unknown parser %q, available parsers are %s", name, strings.Join(parserNames, ","))
+		}
+	}
 	if options.KnownFiles != "" && options.MaxDepth < 3 {
 		gologger.Info().Msgf("Depth automatically set to 3 to accommodate the `--known-files` option (originally set to %d).", options.MaxDepth)
 		options.MaxDepth = 3
@@ -3,6 +3,7 @@ package common
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/url"
@@ -19,17 +20,20 @@ import (
 	"github.com/projectdiscovery/katana/pkg/utils/queue"
 	"github.com/projectdiscovery/retryablehttp-go"
 	"github.com/projectdiscovery/utils/errkit"
-	httputil "github.com/projectdiscovery/utils/http"
 	mapsutil "github.com/projectdiscovery/utils/maps"
 	urlutil "github.com/projectdiscovery/utils/url"
 	"github.com/remeh/sizedwaitgroup"
 )
 
 type Shared struct {
-	Headers    map[string]string
-	KnownFiles *files.KnownFiles
-	Options    *types.CrawlerOptions
-	Jar        *httputil.CookieJar
+	Headers       map[string]string
+	KnownFiles    *files.KnownFiles
+	Options       *types.CrawlerOptions
+	Jar           *CookieJar
+	Authenticator *Authenticator
+	// Router selects the engine of the requests in headless mode, nil if
+	// all the requests are rendered in the browser.
+	Router *Router
 }
 
 func NewShared(options *types.CrawlerOptions) (*Shared, error) {
@@ -42,16 +46,24 @@ func NewShared(options *types.CrawlerOptions) (*Shared, error) {
 		if err != nil {
 			return nil, errkit.Wrap(err, "could not create http client")
 		}
-		shared.KnownFiles = files.New(httpclient, options.Options.KnownFiles)
+		shared.KnownFiles = files.New(httpclient, options.Options.KnownFiles, options.Options.OpenAPI, options.Options.GraphQL)
 	}
 
 	// create an empty cookie jar, this is used to store cookies during the crawl
-	jar, err := httputil.NewCookieJar()
+	jar, err := NewCookieJar()
 	if err != nil {
 		return nil, errkit.Wrap(err, "could not create cookie jar")
 	}
 	shared.Jar = jar
 
+	if options.Options.Headless && !options.Options.HeadlessOnly {
+		router, err := NewRouter(options.Options.HeadlessRoutes)
+		if err != nil {
+			return nil, err
+		}
+		shared.Router = router
+	}
+
 	return shared, nil
 }
 
@@ -93,6 +105,10 @@ func (s *Shared) Enqueue(queue *queue.Queue, navigationRequests ...*navigation.R
 		if nr.Depth > s.Options.Options.MaxDepth {
 			continue
 		}
+		if nr.SkipRequest {
+			s.Output(nr, nil, nil)
+			continue
+		}
 		queue.Push(nr, nr.Depth)
 
 		if s.Options.Options.PathClimb {
@@ -232,6 +248,10 @@ func (s *Shared) NewCrawlSessionWithURL(URL string) (*CrawlSession, error) {
 	return crawlSession, nil
 }
 
+// ErrRequeued is returned by a DoRequestFunc when the request could not
+// be completed and has been pushed back to the queue to be retried.
+var ErrRequeued = errors.New("request requeued")
+
 type DoRequestFunc func(crawlSession *CrawlSession, req *navigation.Request) (*navigation.Response, error)
 
 func (s *Shared) Do(crawlSession *CrawlSession, doRequest DoRequestFunc) error {
@@ -282,6 +302,15 @@ func (s *Shared) Do(crawlSession *CrawlSession, doRequest DoRequestFunc) error {
 			}
 
 			resp, err := doRequest(crawlSession, req)
+			if errors.Is(err, ErrRequeued) {
+				return
+			}
+
+			// retry the request with a fresh session if the current one has expired
+			if err == nil && s.reauthenticate(req, resp) {
+				crawlSession.Queue.Push(req, req.Depth)
+				return
+			}
 
 			if inScope {
 				s.Output(req, resp, err)
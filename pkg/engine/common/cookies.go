@@ -0,0 +1,129 @@
+package common
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	httputil "github.com/projectdiscovery/utils/http"
+)
+
+// CookieJar is the cookie jar of the crawl. Unlike http.CookieJar, it keeps
+// the domain and path of the stored cookies so that they can be set in the
+// browser with their original scope.
+type CookieJar struct {
+	*httputil.CookieJar
+
+	mutex sync.RWMutex
+	// scopes are the stored cookies keyed by their domain, or host for
+	// host-only cookies, path and name.
+	scopes map[string]*scopedCookie
+}
+
+// scopedCookie is a stored cookie, the domain of host-only cookies is empty
+type scopedCookie struct {
+	cookie *http.Cookie
+	host   string
+}
+
+// matches returns true if the cookie is sent to the host and path
+func (c *scopedCookie) matches(host, path string) bool {
+	if !cookiePathMatch(c.cookie.Path, path) {
+		return false
+	}
+	if c.cookie.Domain == "" {
+		return c.host == host
+	}
+	return host == c.cookie.Domain || strings.HasSuffix(host, "."+c.cookie.Domain)
+}
+
+// NewCookieJar returns an empty cookie jar
+func NewCookieJar() (*CookieJar, error) {
+	jar, err := httputil.NewCookieJar()
+	if err != nil {
+		return nil, err
+	}
+	return &CookieJar{CookieJar: jar, scopes: make(map[string]*scopedCookie)}, nil
+}
+
+// SetCookies stores the cookies received from the url
+func (j *CookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.CookieJar.SetCookies(u, cookies)
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	host := strings.ToLower(u.Hostname())
+	for _, cookie := range cookies {
+		scoped := *cookie
+		scoped.Domain = strings.ToLower(strings.TrimPrefix(cookie.Domain, "."))
+		if scoped.Path == "" || !strings.HasPrefix(scoped.Path, "/") {
+			scoped.Path = defaultCookiePath(u.Path)
+		}
+		stored := &scopedCookie{cookie: &scoped}
+		if scoped.Domain == "" {
+			stored.host = host
+		}
+		j.scopes[scoped.Domain+";"+stored.host+";"+scoped.Path+";"+scoped.Name] = stored
+	}
+}
+
+// ScopedCookies returns the cookies to send to the url with their domain and
+// path. The domain of host-only cookies is empty.
+func (j *CookieJar) ScopedCookies(u *url.URL) []*http.Cookie {
+	cookies := j.Cookies(u)
+
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	host := strings.ToLower(u.Hostname())
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	// cookies are returned longest path first, each one is matched with the
+	// stored cookie having the longest path among the unmatched ones.
+	matched := make(map[*scopedCookie]struct{})
+	scopedCookies := make([]*http.Cookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		var selected *scopedCookie
+		for _, stored := range j.scopes {
+			if _, ok := matched[stored]; ok || stored.cookie.Name != cookie.Name || stored.cookie.Value != cookie.Value || !stored.matches(host, path) {
+				continue
+			}
+			if selected == nil || len(stored.cookie.Path) > len(selected.cookie.Path) {
+				selected = stored
+			}
+		}
+		if selected == nil {
+			scopedCookies = append(scopedCookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value})
+			continue
+		}
+		matched[selected] = struct{}{}
+		scoped := *selected.cookie
+		scopedCookies = append(scopedCookies, &scoped)
+	}
+	return scopedCookies
+}
+
+// defaultCookiePath returns the default path of the cookies of a url path (RFC 6265 5.1.4)
+func defaultCookiePath(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		return "/"
+	}
+	index := strings.LastIndex(path, "/")
+	if index == 0 {
+		return "/"
+	}
+	return path[:index]
+}
+
+// cookiePathMatch returns true if the cookie path matches the request path (RFC 6265 5.1.4)
+func cookiePathMatch(cookiePath, requestPath string) bool {
+	if cookiePath == requestPath {
+		return true
+	}
+	if !strings.HasPrefix(requestPath, cookiePath) {
+		return false
+	}
+	return strings.HasSuffix(cookiePath, "/") || requestPath[len(cookiePath)] == '/'
+}
@@ -0,0 +1,57 @@
+package common
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCookieJarScopedCookies(t *testing.T) {
+	jar, err := NewCookieJar()
+	require.Nil(t, err, "could not create cookie jar")
+
+	origin, _ := url.Parse("https://www.example.com/account/login")
+	jar.SetCookies(origin, []*http.Cookie{
+		{Name: "sid", Value: "1", Domain: "example.com", Path: "/"},
+		{Name: "csrf", Value: "2", Path: "/account"},
+		{Name: "pref", Value: "3"},
+		{Name: "pref", Value: "4", Path: "/account/settings"},
+	})
+
+	cookies := func(rawURL string) map[string]http.Cookie {
+		parsed, _ := url.Parse(rawURL)
+		values := make(map[string]http.Cookie)
+		for _, cookie := range jar.ScopedCookies(parsed) {
+			values[cookie.Name+"="+cookie.Value] = *cookie
+		}
+		return values
+	}
+
+	require.Equal(t, map[string]http.Cookie{
+		"sid=1":  {Name: "sid", Value: "1", Domain: "example.com", Path: "/"},
+		"csrf=2": {Name: "csrf", Value: "2", Path: "/account"},
+		"pref=3": {Name: "pref", Value: "3", Path: "/account"},
+		"pref=4": {Name: "pref", Value: "4", Path: "/account/settings"},
+	}, cookies("https://www.example.com/account/settings/profile"))
+
+	// host-only cookies are not sent to the other hosts of the domain
+	require.Equal(t, map[string]http.Cookie{
+		"sid=1": {Name: "sid", Value: "1", Domain: "example.com", Path: "/"},
+	}, cookies("https://api.example.com/account/"))
+	require.Empty(t, cookies("https://example.org/"))
+}
+
+func TestCookiePathMatch(t *testing.T) {
+	require.True(t, cookiePathMatch("/", "/a"))
+	require.True(t, cookiePathMatch("/a", "/a"))
+	require.True(t, cookiePathMatch("/a", "/a/b"))
+	require.True(t, cookiePathMatch("/a/", "/a/b"))
+	require.False(t, cookiePathMatch("/a", "/ab"))
+	require.False(t, cookiePathMatch("/a/b", "/a"))
+
+	require.Equal(t, "/", defaultCookiePath(""))
+	require.Equal(t, "/", defaultCookiePath("/login"))
+	require.Equal(t, "/account", defaultCookiePath("/account/login"))
+}
@@ -0,0 +1,96 @@
+package common
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/katana/pkg/engine/login"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/utils/errkit"
+)
+
+// minReloginInterval is the minimum interval between two login flow executions
+// so that concurrent requests detecting the same expiry do not log in again.
+const minReloginInterval = 10 * time.Second
+
+// LoginFunc executes the login flow returning the authenticated session
+type LoginFunc func(flow *login.Flow) (*login.Session, error)
+
+// Authenticator runs the login flow and keeps track of the current session
+type Authenticator struct {
+	flow      *login.Flow
+	execute   LoginFunc
+	mutex     sync.RWMutex
+	session   *login.Session
+	lastLogin time.Time
+	// generation is incremented on every successful login
+	generation int
+	// retried tracks the requests retried after a login of a generation
+	retried sync.Map
+}
+
+// Authenticate executes the login flow configured in the options before the crawl
+// starts, the resulting cookies are stored in the shared cookie jar.
+func (s *Shared) Authenticate(execute LoginFunc) error {
+	if s.Options.Options.LoginFlow == "" {
+		return nil
+	}
+	flow, err := login.LoadFlow(s.Options.Options.LoginFlow)
+	if err != nil {
+		return err
+	}
+	s.Authenticator = &Authenticator{flow: flow, execute: execute}
+	return s.login()
+}
+
+// Session returns the current authenticated session
+func (a *Authenticator) Session() *login.Session {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.session
+}
+
+func (s *Shared) login() error {
+	a := s.Authenticator
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if time.Since(a.lastLogin) < minReloginInterval {
+		return nil
+	}
+	session, err := a.execute(a.flow)
+	if err != nil {
+		return errkit.Wrap(err, "could not execute login flow")
+	}
+	a.session = session
+	a.lastLogin = time.Now()
+	a.generation++
+	if s.Jar != nil {
+		session.SetCookies(s.Jar)
+	}
+	gologger.Info().Msgf("Login flow executed successfully (%d cookies)", len(session.Cookies))
+	return nil
+}
+
+// reauthenticate re-runs the login flow if the response indicates an expired
+// session. It returns true if the request should be retried.
+func (s *Shared) reauthenticate(req *navigation.Request, resp *navigation.Response) bool {
+	a := s.Authenticator
+	if a == nil || !a.flow.IsExpired(req, resp) {
+		return false
+	}
+	gologger.Info().Msgf("Session expiry detected on %s, executing login flow", req.URL)
+	if err := s.login(); err != nil {
+		gologger.Warning().Msgf("Could not re-authenticate: %s\n", err)
+		return false
+	}
+	// each request is only retried once per session to avoid loops on broken
+	// flows, expiries of the sessions of later logins are retried again
+	a.mutex.RLock()
+	key := strconv.Itoa(a.generation) + ":" + req.RequestURL()
+	a.mutex.RUnlock()
+	_, retried := a.retried.LoadOrStore(key, struct{}{})
+	return !retried
+}
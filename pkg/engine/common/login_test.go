@@ -0,0 +1,37 @@
+package common
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/katana/pkg/engine/login"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReauthenticate(t *testing.T) {
+	var logins int
+	shared := &Shared{Authenticator: &Authenticator{
+		flow: &login.Flow{Expiry: login.Expiry{StatusCodes: []int{http.StatusUnauthorized}}},
+		execute: func(flow *login.Flow) (*login.Session, error) {
+			logins++
+			return &login.Session{}, nil
+		},
+	}}
+	request := &navigation.Request{Method: http.MethodGet, URL: "https://example.com/account"}
+	expired := &navigation.Response{Resp: &http.Response{}, StatusCode: http.StatusUnauthorized}
+
+	require.False(t, shared.reauthenticate(request, &navigation.Response{Resp: &http.Response{}, StatusCode: http.StatusOK}))
+	require.True(t, shared.reauthenticate(request, expired))
+	require.Equal(t, 1, logins)
+
+	// the retried request expiring again with the same session is not retried
+	require.False(t, shared.reauthenticate(request, expired))
+	require.Equal(t, 1, logins)
+
+	// a later expiry is re-authenticated and retried with the new session
+	shared.Authenticator.lastLogin = time.Now().Add(-minReloginInterval)
+	require.True(t, shared.reauthenticate(request, expired))
+	require.Equal(t, 2, logins)
+}
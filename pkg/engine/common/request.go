@@ -1,4 +1,4 @@
-package standard
+package common
 
 import (
 	"bytes"
@@ -10,7 +10,6 @@ import (
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/projectdiscovery/katana/pkg/engine/common"
 	"github.com/projectdiscovery/katana/pkg/navigation"
 	"github.com/projectdiscovery/katana/pkg/utils"
 	"github.com/projectdiscovery/retryablehttp-go"
@@ -18,13 +17,14 @@ import (
 	mapsutil "github.com/projectdiscovery/utils/maps"
 )
 
-// makeRequest makes a request to a URL returning a response interface.
-func (c *Crawler) makeRequest(s *common.CrawlSession, request *navigation.Request) (*navigation.Response, error) {
+// MakeRequest makes a request to a URL with the http client of the crawl session
+// returning a response interface.
+func (s *Shared) MakeRequest(crawlSession *CrawlSession, request *navigation.Request) (*navigation.Response, error) {
 	response := &navigation.Response{
 		Depth:        request.Depth + 1,
-		RootHostname: s.Hostname,
+		RootHostname: crawlSession.Hostname,
 	}
-	ctx := context.WithValue(s.Ctx, navigation.Depth{}, request.Depth)
+	ctx := context.WithValue(crawlSession.Ctx, navigation.Depth{}, request.Depth)
 	httpReq, err := http.NewRequestWithContext(ctx, request.Method, request.URL, nil)
 	if err != nil {
 		return response, err
@@ -46,7 +46,7 @@ func (c *Crawler) makeRequest(s *common.CrawlSession, request *navigation.Reques
 		}
 	}
 
-	for k, v := range c.Headers {
+	for k, v := range s.Headers {
 		req.Header.Set(k, v)
 		if k == "Host" {
 			req.Host = v
@@ -54,14 +54,14 @@ func (c *Crawler) makeRequest(s *common.CrawlSession, request *navigation.Reques
 	}
 
 	// Apply cookies
-	if c.Jar != nil {
-		cookies := c.Jar.Cookies(req.Request.URL)
+	if s.Jar != nil {
+		cookies := s.Jar.Cookies(req.Request.URL)
 		for _, cookie := range cookies {
 			req.AddCookie(cookie)
 		}
 	}
 
-	resp, err := s.HttpClient.Do(req)
+	resp, err := crawlSession.HttpClient.Do(req)
 	if resp != nil {
 		defer func() {
 			if resp.Body != nil && resp.StatusCode != http.StatusSwitchingProtocols {
@@ -72,8 +72,8 @@ func (c *Crawler) makeRequest(s *common.CrawlSession, request *navigation.Reques
 	}
 
 	// Collect cookies from the response
-	if c.Jar != nil && resp != nil {
-		c.Jar.SetCookies(req.Request.URL, resp.Cookies())
+	if s.Jar != nil && resp != nil {
+		s.Jar.SetCookies(req.Request.URL, resp.Cookies())
 	}
 
 	rawRequestBytes, _ := req.Dump()
@@ -85,20 +85,36 @@ func (c *Crawler) makeRequest(s *common.CrawlSession, request *navigation.Reques
 	if resp.StatusCode == http.StatusSwitchingProtocols {
 		return response, nil
 	}
-	limitReader := io.LimitReader(resp.Body, int64(c.Options.Options.BodyReadSize))
+	readSize := int64(s.Options.Options.BodyReadSize)
+	// documents are read whole within their own size cap to be parsed
+	isDocument := s.Options.Options.DocumentCrawl && utils.DocumentType(resp.Header.Get("Content-Type"), resp.Request.URL.Path) != ""
+	documentMaxSize := int64(s.Options.Options.DocumentMaxSize)
+	if isDocument && resp.ContentLength <= documentMaxSize {
+		readSize = documentMaxSize
+	}
+	// one more byte is read to tell truncated bodies apart
+	limitReader := io.LimitReader(resp.Body, readSize+1)
 	data, err := io.ReadAll(limitReader)
 	if err != nil {
 		return response, err
 	}
+	if int64(len(data)) > readSize {
+		data = data[:readSize]
+		response.Truncated = true
+	}
+	// documents declared larger than their size cap are not parsed either
+	if isDocument && resp.ContentLength > documentMaxSize {
+		response.Truncated = true
+	}
 	// Skip unique content filtering if disabled
-	if !c.Options.Options.DisableUniqueFilter {
-		if !c.Options.UniqueFilter.UniqueContent(data) {
+	if !s.Options.Options.DisableUniqueFilter {
+		if !s.Options.UniqueFilter.UniqueContent(data) {
 			return &navigation.Response{}, nil
 		}
 	}
 
-	if c.Options.Wappalyzer != nil {
-		technologies := c.Options.Wappalyzer.Fingerprint(resp.Header, data)
+	if s.Options.Wappalyzer != nil {
+		technologies := s.Options.Wappalyzer.Fingerprint(resp.Header, data)
 		response.Technologies = mapsutil.GetKeys(technologies)
 	}
 
@@ -110,7 +126,7 @@ func (c *Crawler) makeRequest(s *common.CrawlSession, request *navigation.Reques
 	response.Reader.Url, _ = url.Parse(request.URL)
 	response.StatusCode = resp.StatusCode
 	response.Headers = utils.FlattenHeaders(resp.Header)
-	if c.Options.Options.FormExtraction {
+	if s.Options.Options.FormExtraction {
 		response.Forms = append(response.Forms, utils.ParseFormFields(response.Reader)...)
 	}
 
@@ -121,7 +137,7 @@ func (c *Crawler) makeRequest(s *common.CrawlSession, request *navigation.Reques
 	response.Raw = string(rawResponseBytes)
 
 	if err != nil {
-		return response, errkit.Wrap(err, "standard: could not make document from reader")
+		return response, errkit.Wrap(err, "could not make document from reader")
 	}
 
 	return response, nil
@@ -0,0 +1,60 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/types"
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeRequestDocumentMaxSize(t *testing.T) {
+	document := "%PDF-1.7\n" + strings.Repeat("a", 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		switch r.URL.Path {
+		case "/chunked.pdf":
+			// the size is not declared for chunked responses
+			w.(http.Flusher).Flush()
+		default:
+			w.Header().Set("Content-Length", strconv.Itoa(len(document)))
+		}
+		_, _ = w.Write([]byte(document))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name            string
+		path            string
+		documentMaxSize int
+		truncated       bool
+	}{
+		{name: "within limit", path: "/report.pdf", documentMaxSize: 1024},
+		{name: "declared over limit", path: "/report.pdf", documentMaxSize: 50, truncated: true},
+		{name: "read over limit", path: "/chunked.pdf", documentMaxSize: 50, truncated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shared := &Shared{Options: &types.CrawlerOptions{Options: &types.Options{
+				BodyReadSize:        1024,
+				DocumentCrawl:       true,
+				DocumentMaxSize:     tt.documentMaxSize,
+				DisableUniqueFilter: true,
+			}}}
+			session := &CrawlSession{Ctx: context.Background(), HttpClient: retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)}
+
+			response, err := shared.MakeRequest(session, &navigation.Request{Method: http.MethodGet, URL: server.URL + tt.path})
+			require.Nil(t, err)
+			require.Equal(t, tt.truncated, response.Truncated)
+			if !tt.truncated {
+				require.Equal(t, document, response.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package common
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/utils/errkit"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+// Engine is the engine a request is sent with during hybrid crawling
+type Engine string
+
+const (
+	// EngineHeadless renders the request in the browser
+	EngineHeadless Engine = "headless"
+	// EngineStandard sends the request with the http client
+	EngineStandard Engine = "standard"
+)
+
+const (
+	// spaShellMaxText is the maximum visible text length of an spa shell
+	spaShellMaxText = 256
+	// spaShellMaxLinks is the maximum number of links of an spa shell
+	spaShellMaxLinks = 2
+)
+
+// staticExtensions are the extensions of resources which are never rendered
+// in the browser, they are fetched with the http client.
+var staticExtensions = map[string]struct{}{
+	".js": {}, ".mjs": {}, ".cjs": {}, ".jsx": {}, ".ts": {}, ".map": {}, ".json": {}, ".jsonld": {}, ".webmanifest": {},
+	".xml": {}, ".rss": {}, ".atom": {}, ".txt": {}, ".csv": {}, ".yaml": {}, ".yml": {}, ".css": {}, ".wasm": {},
+	".png": {}, ".jpg": {}, ".jpeg": {}, ".gif": {}, ".bmp": {}, ".ico": {}, ".svg": {}, ".webp": {}, ".avif": {}, ".tif": {}, ".tiff": {},
+	".woff": {}, ".woff2": {}, ".ttf": {}, ".otf": {}, ".eot": {},
+	".mp3": {}, ".mp4": {}, ".m4a": {}, ".m4v": {}, ".ogg": {}, ".ogv": {}, ".wav": {}, ".webm": {}, ".avi": {}, ".mov": {}, ".flv": {},
+	".pdf": {}, ".doc": {}, ".docx": {}, ".xls": {}, ".xlsx": {}, ".ppt": {}, ".pptx": {}, ".odt": {},
+	".zip": {}, ".gz": {}, ".tar": {}, ".rar": {}, ".7z": {}, ".exe": {}, ".msi": {}, ".apk": {}, ".dmg": {}, ".iso": {},
+}
+
+// staticAttributes are the tag attributes referencing resources which are
+// never rendered in the browser, e.g. <script src> or <img src>.
+var staticAttributes = map[string]struct{}{
+	"script:src": {}, "img:src": {}, "img:srcset": {}, "img:lowsrc": {}, "img:dynsrc": {},
+	"audio:src": {}, "audio:source": {}, "audio:sourcesrcset": {}, "video:src": {}, "video:poster": {}, "video:track-src": {},
+	"input-image:src": {}, "embed:src": {}, "applet:archive": {}, "svg:image-href": {}, "svg:script-href": {},
+	"body:background": {}, "table:background": {}, "table:td-background": {}, "html:manifest": {},
+	// xhr requests captured in the browser and replayed
+	"xhr:replay": {},
+}
+
+// routeRule sends the urls matching the pattern with the engine
+type routeRule struct {
+	pattern *regexp.Regexp
+	engine  Engine
+}
+
+// Router selects the engine of each request during hybrid crawling
+type Router struct {
+	rules []routeRule
+}
+
+// NewRouter returns a router for the rules, each one formatted as
+// '<url-regex> => headless|standard'.
+func NewRouter(rules []string) (*Router, error) {
+	router := &Router{}
+	for _, rule := range rules {
+		pattern, engine, found := strings.Cut(rule, "=>")
+		if !found {
+			return nil, errkit.Newf("invalid route rule %q, expected <url-regex> => headless|standard", rule)
+		}
+		regex, err := regexp.Compile(strings.TrimSpace(pattern))
+		if err != nil {
+			return nil, errkit.Wrap(err, "invalid route rule regex")
+		}
+		routeEngine := Engine(strings.ToLower(strings.TrimSpace(engine)))
+		if routeEngine != EngineHeadless && routeEngine != EngineStandard {
+			return nil, errkit.Newf("invalid route rule engine %q, expected headless or standard", engine)
+		}
+		router.rules = append(router.rules, routeRule{pattern: regex, engine: routeEngine})
+	}
+	return router, nil
+}
+
+// Route returns the engine of the request. The first matching rule wins, other
+// requests are rendered in the browser unless they reference a static resource.
+// The returned bool is true if the request references a static resource.
+func (r *Router) Route(request *navigation.Request) (Engine, bool) {
+	for _, rule := range r.rules {
+		if rule.pattern.MatchString(request.URL) {
+			return rule.engine, false
+		}
+	}
+	if _, ok := staticAttributes[request.Tag+":"+request.Attribute]; ok {
+		return EngineStandard, true
+	}
+	parsed, err := urlutil.Parse(request.URL)
+	if err != nil {
+		return EngineHeadless, false
+	}
+	if _, ok := staticExtensions[strings.ToLower(path.Ext(parsed.Path))]; ok {
+		return EngineStandard, true
+	}
+	return EngineHeadless, false
+}
+
+// IsSPAShell returns true if the response is an html document whose content
+// is rendered by javascript, i.e. it has scripts but almost no text nor links.
+func IsSPAShell(response *navigation.Response) bool {
+	if response == nil || response.Resp == nil || response.Reader == nil {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(response.Resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return false
+	}
+	if response.Reader.Find("script").Length() == 0 {
+		return false
+	}
+	if response.Reader.Find("a[href]").Length() > spaShellMaxLinks {
+		return false
+	}
+
+	body := response.Reader.Find("body").Clone()
+	body.Find("script, style, noscript, template").Remove()
+	return len(strings.Join(strings.Fields(body.Text()), " ")) <= spaShellMaxText
+}
+
+// Route returns a request function sending the requests routed to the browser
+// with headless, and the other ones with the http client of the crawl session.
+// Responses of the http client looking like spa shells are escalated to the browser.
+func (s *Shared) Route(headless DoRequestFunc) DoRequestFunc {
+	// all the requests are rendered in the browser in headless only mode
+	if s.Router == nil {
+		return headless
+	}
+	return func(crawlSession *CrawlSession, request *navigation.Request) (*navigation.Response, error) {
+		// the browser can only navigate to get requests, other methods
+		// (e.g. replayed xhr requests) are sent with the http client
+		if request.Method != http.MethodGet {
+			return s.MakeRequest(crawlSession, request)
+		}
+
+		engine, static := s.Router.Route(request)
+		if engine == EngineHeadless {
+			return headless(crawlSession, request)
+		}
+
+		// static resources answered with an html page are usually the
+		// fallback page of the application and are not escalated.
+		response, err := s.MakeRequest(crawlSession, request)
+		if err != nil || static || !IsSPAShell(response) {
+			return response, err
+		}
+		gologger.Debug().Msgf("Escalating spa shell %s to headless\n", request.URL)
+		return headless(crawlSession, request)
+	}
+}
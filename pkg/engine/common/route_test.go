@@ -0,0 +1,77 @@
+package common
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterRoute(t *testing.T) {
+	router, err := NewRouter([]string{`/static/.*\.html$ => standard`, `/api/render => headless`})
+	require.Nil(t, err, "could not create router")
+
+	tests := []struct {
+		request *navigation.Request
+		engine  Engine
+		static  bool
+	}{
+		{request: &navigation.Request{URL: "https://example.com/"}, engine: EngineHeadless},
+		{request: &navigation.Request{URL: "https://example.com/app.js?v=1"}, engine: EngineStandard, static: true},
+		{request: &navigation.Request{URL: "https://example.com/robots.txt"}, engine: EngineStandard, static: true},
+		{request: &navigation.Request{URL: "https://example.com/logo", Tag: "img", Attribute: "src"}, engine: EngineStandard, static: true},
+		{request: &navigation.Request{URL: "https://example.com/api/users", Tag: "xhr", Attribute: "replay"}, engine: EngineStandard, static: true},
+		{request: &navigation.Request{URL: "https://example.com/static/page.html"}, engine: EngineStandard},
+		{request: &navigation.Request{URL: "https://example.com/api/render.json"}, engine: EngineHeadless},
+	}
+	for _, test := range tests {
+		engine, static := router.Route(test.request)
+		require.Equal(t, test.engine, engine, "invalid engine for %s", test.request.URL)
+		require.Equal(t, test.static, static, "invalid static for %s", test.request.URL)
+	}
+
+	for _, rule := range []string{"/static", "/static => browser", "[ => standard"} {
+		_, err := NewRouter([]string{rule})
+		require.NotNil(t, err, "invalid route rule %q was parsed", rule)
+	}
+}
+
+func TestIsSPAShell(t *testing.T) {
+	newResponse := func(contentType, body string) *navigation.Response {
+		reader, _ := goquery.NewDocumentFromReader(strings.NewReader(body))
+		return &navigation.Response{
+			Resp:   &http.Response{Header: http.Header{"Content-Type": []string{contentType}}},
+			Body:   body,
+			Reader: reader,
+		}
+	}
+
+	shell := `<html><head><script src="/main.js"></script></head><body><div id="root"></div><noscript>You need to enable JavaScript to run this app.</noscript></body></html>`
+	require.True(t, IsSPAShell(newResponse("text/html; charset=utf-8", shell)), "spa shell not detected")
+	require.False(t, IsSPAShell(newResponse("application/json", shell)), "non html response detected as spa shell")
+
+	page := `<html><body><script src="/main.js"></script><a href="/a">a</a><a href="/b">b</a><a href="/c">c</a></body></html>`
+	require.False(t, IsSPAShell(newResponse("text/html", page)), "page with links detected as spa shell")
+
+	static := `<html><body><h1>Welcome</h1><p>Static content</p></body></html>`
+	require.False(t, IsSPAShell(newResponse("text/html", static)), "page without scripts detected as spa shell")
+}
+
+func TestSharedRouteHeadlessOnly(t *testing.T) {
+	var rendered []string
+	headless := func(crawlSession *CrawlSession, request *navigation.Request) (*navigation.Response, error) {
+		rendered = append(rendered, request.Method)
+		return &navigation.Response{}, nil
+	}
+
+	// without router every request is rendered in the browser, whatever its method
+	route := (&Shared{}).Route(headless)
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		_, err := route(nil, &navigation.Request{Method: method, URL: "https://example.com/"})
+		require.Nil(t, err, "could not route %s request", method)
+	}
+	require.Equal(t, []string{http.MethodGet, http.MethodPost}, rendered)
+}
@@ -0,0 +1,163 @@
+package hybrid
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/katana/pkg/engine/common"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/types"
+	"github.com/projectdiscovery/utils/errkit"
+	"golang.org/x/net/publicsuffix"
+)
+
+// blockableResourceTypes are the resource types which can be blocked
+var blockableResourceTypes = []proto.NetworkResourceType{
+	proto.NetworkResourceTypeStylesheet,
+	proto.NetworkResourceTypeImage,
+	proto.NetworkResourceTypeMedia,
+	proto.NetworkResourceTypeFont,
+	proto.NetworkResourceTypeScript,
+	proto.NetworkResourceTypeTextTrack,
+	proto.NetworkResourceTypeXHR,
+	proto.NetworkResourceTypeFetch,
+	proto.NetworkResourceTypePrefetch,
+	proto.NetworkResourceTypeEventSource,
+	proto.NetworkResourceTypeWebSocket,
+	proto.NetworkResourceTypeManifest,
+	proto.NetworkResourceTypePing,
+	proto.NetworkResourceTypeCSPViolationReport,
+	proto.NetworkResourceTypeOther,
+}
+
+// requestBlocker decides which requests made by a page are failed
+// instead of being sent to the network.
+type requestBlocker struct {
+	resourceTypes map[proto.NetworkResourceType]struct{}
+	urlPatterns   []*regexp.Regexp
+	thirdParty    bool
+}
+
+// newRequestBlocker returns a request blocker for the options or nil if blocking is disabled
+func newRequestBlocker(options *types.Options) (*requestBlocker, error) {
+	if len(options.HeadlessBlockResources) == 0 && len(options.HeadlessBlockURLs) == 0 && !options.HeadlessBlockThirdParty {
+		return nil, nil
+	}
+
+	blocker := &requestBlocker{
+		resourceTypes: make(map[proto.NetworkResourceType]struct{}),
+		thirdParty:    options.HeadlessBlockThirdParty,
+	}
+	for _, value := range options.HeadlessBlockResources {
+		resourceType, ok := parseResourceType(value)
+		if !ok {
+			return nil, errkit.Newf("hybrid: invalid resource type to block %s", value)
+		}
+		blocker.resourceTypes[resourceType] = struct{}{}
+	}
+	for _, value := range options.HeadlessBlockURLs {
+		compiled, err := regexp.Compile(value)
+		if err != nil {
+			return nil, errkit.Wrap(err, "hybrid: invalid block url regex")
+		}
+		blocker.urlPatterns = append(blocker.urlPatterns, compiled)
+	}
+	return blocker, nil
+}
+
+func parseResourceType(value string) (proto.NetworkResourceType, bool) {
+	for _, resourceType := range blockableResourceTypes {
+		if strings.EqualFold(string(resourceType), strings.TrimSpace(value)) {
+			return resourceType, true
+		}
+	}
+	return "", false
+}
+
+// shouldBlock returns true if the paused request of the page should be blocked
+func (b *requestBlocker) shouldBlock(pageURL string, e *proto.FetchRequestPaused) bool {
+	// never block documents, they are the pages being crawled
+	if e.ResourceType == proto.NetworkResourceTypeDocument {
+		return false
+	}
+	if _, ok := b.resourceTypes[e.ResourceType]; ok {
+		return true
+	}
+	for _, pattern := range b.urlPatterns {
+		if pattern.MatchString(e.Request.URL) {
+			return true
+		}
+	}
+	return b.thirdParty && isThirdParty(e.Request.URL, pageURL)
+}
+
+// isThirdParty returns true if the request url is not on the registrable
+// domain of the page url, e.g. cdn.example.com is not third-party for
+// www.example.com.
+func isThirdParty(requestURL, pageURL string) bool {
+	requestHost, pageHost := urlHostname(requestURL), urlHostname(pageURL)
+	// urls without host, e.g. data: or blob: urls, are never third-party
+	if requestHost == "" || pageHost == "" {
+		return false
+	}
+	return registrableDomain(requestHost) != registrableDomain(pageHost)
+}
+
+// registrableDomain returns the registrable domain (etld+1) of a hostname,
+// or the hostname itself for ip addresses and single label hosts.
+func registrableDomain(hostname string) string {
+	if domain, err := publicsuffix.EffectiveTLDPlusOne(hostname); err == nil {
+		return domain
+	}
+	return hostname
+}
+
+// urlHostname returns the lowercase hostname of a url
+func urlHostname(value string) string {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// continueOrBlock fails a paused request of the page if it is blocked,
+// recording it when enabled, and continues it otherwise.
+func (c *Crawler) continueOrBlock(s *common.CrawlSession, page *rod.Page, request *navigation.Request, e *proto.FetchRequestPaused) error {
+	if c.blocker != nil && c.blocker.shouldBlock(request.URL, e) {
+		c.recordBlockedRequest(s, request, e)
+		return FetchFailRequest(page, e, proto.NetworkErrorReasonBlockedByClient)
+	}
+	return FetchContinueRequest(page, e)
+}
+
+// recordBlockedRequest writes a blocked request to the output as a discovered url
+func (c *Crawler) recordBlockedRequest(s *common.CrawlSession, request *navigation.Request, e *proto.FetchRequestPaused) {
+	if !c.Options.Options.HeadlessRecordBlocked {
+		return
+	}
+	if !c.Options.UniqueFilter.UniqueURL(e.Request.URL) {
+		return
+	}
+	if !c.ValidateScope(e.Request.URL, s.Hostname) && !c.Options.Options.DisplayOutScope {
+		return
+	}
+	method := e.Request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	blockedRequest := &navigation.Request{
+		Method:       method,
+		URL:          e.Request.URL,
+		Depth:        request.Depth + 1,
+		RootHostname: s.Hostname,
+		Source:       request.URL,
+		Tag:          "blocked",
+		Attribute:    strings.ToLower(string(e.ResourceType)),
+	}
+	c.Output(blockedRequest, nil, nil)
+}
@@ -0,0 +1,76 @@
+package hybrid
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/katana/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResourceType(t *testing.T) {
+	tests := []struct {
+		value        string
+		resourceType proto.NetworkResourceType
+		ok           bool
+	}{
+		{"image", proto.NetworkResourceTypeImage, true},
+		{" Stylesheet ", proto.NetworkResourceTypeStylesheet, true},
+		{"XHR", proto.NetworkResourceTypeXHR, true},
+		{"websocket", proto.NetworkResourceTypeWebSocket, true},
+		{"document", "", false},
+		{"unknown", "", false},
+		{"", "", false},
+	}
+	for _, test := range tests {
+		resourceType, ok := parseResourceType(test.value)
+		require.Equal(t, test.ok, ok, "invalid result for %q", test.value)
+		require.Equal(t, test.resourceType, resourceType, "invalid resource type for %q", test.value)
+	}
+}
+
+func TestShouldBlock(t *testing.T) {
+	blocker, err := newRequestBlocker(&types.Options{
+		HeadlessBlockResources:  []string{"image", "font"},
+		HeadlessBlockURLs:       []string{`/analytics\.js$`},
+		HeadlessBlockThirdParty: true,
+	})
+	require.Nil(t, err, "could not create request blocker")
+
+	const pageURL = "https://www.example.co.uk/app/"
+	tests := []struct {
+		url          string
+		resourceType proto.NetworkResourceType
+		blocked      bool
+	}{
+		{"https://tracker.net/pixel", proto.NetworkResourceTypeDocument, false},
+		{"https://www.example.co.uk/logo.png", proto.NetworkResourceTypeImage, true},
+		{"https://www.example.co.uk/static/analytics.js", proto.NetworkResourceTypeScript, true},
+		{"https://www.example.co.uk/app.js", proto.NetworkResourceTypeScript, false},
+		// requests on the registrable domain of the page are not third-party,
+		// whatever their path or subdomain
+		{"https://www.example.co.uk/out-of-scope/api", proto.NetworkResourceTypeXHR, false},
+		{"https://cdn.example.co.uk/app.css", proto.NetworkResourceTypeStylesheet, false},
+		{"https://example.com/app.css", proto.NetworkResourceTypeStylesheet, true},
+		{"https://other.co.uk/app.js", proto.NetworkResourceTypeScript, true},
+		{"data:text/css,body{}", proto.NetworkResourceTypeStylesheet, false},
+	}
+	for _, test := range tests {
+		e := &proto.FetchRequestPaused{Request: &proto.NetworkRequest{URL: test.url}, ResourceType: test.resourceType}
+		require.Equal(t, test.blocked, blocker.shouldBlock(pageURL, e), "invalid blocking of %s", test.url)
+	}
+
+	blocker, err = newRequestBlocker(&types.Options{})
+	require.Nil(t, err)
+	require.Nil(t, blocker, "blocker created without blocking options")
+
+	_, err = newRequestBlocker(&types.Options{HeadlessBlockResources: []string{"document"}})
+	require.NotNil(t, err, "invalid resource type was parsed")
+}
+
+func TestIsThirdParty(t *testing.T) {
+	require.False(t, isThirdParty("http://127.0.0.1:8080/api", "http://127.0.0.1/"))
+	require.True(t, isThirdParty("http://127.0.0.2/api", "http://127.0.0.1/"))
+	require.False(t, isThirdParty("http://LOCALHOST/api", "http://localhost:3000/"))
+	require.True(t, isThirdParty("https://user.github.io/", "https://other.github.io/"))
+}
@@ -0,0 +1,176 @@
+package hybrid
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+)
+
+// blockedByClientError is the network error logged by the browser for the requests
+// failed by the request blocker
+const blockedByClientError = "net::ERR_BLOCKED_BY_CLIENT"
+
+// consoleCollector collects the javascript runtime events of a page
+type consoleCollector struct {
+	sync.Mutex
+	messages   []navigation.ConsoleMessage
+	exceptions []navigation.JSException
+	failed     []navigation.FailedResource
+}
+
+// captureConsole starts collecting console messages, uncaught exceptions and
+// failed resource loads of the page. The returned function stops the collection.
+func (c *Crawler) captureConsole(page *rod.Page) (*consoleCollector, func()) {
+	collector := &consoleCollector{}
+	if !c.Options.Options.ConsoleCapture {
+		return collector, func() {}
+	}
+
+	page, cancel := page.WithCancel()
+	wait := page.EachEvent(
+		func(e *proto.RuntimeConsoleAPICalled) {
+			collector.addConsoleAPICall(e)
+		},
+		func(e *proto.RuntimeExceptionThrown) {
+			collector.addException(e.ExceptionDetails)
+		},
+		func(e *proto.LogEntryAdded) {
+			collector.addLogEntry(e.Entry)
+		},
+	)
+	go wait()
+	return collector, cancel
+}
+
+func (cc *consoleCollector) addConsoleAPICall(e *proto.RuntimeConsoleAPICalled) {
+	args := make([]string, 0, len(e.Args))
+	for _, arg := range e.Args {
+		args = append(args, formatRemoteObject(arg))
+	}
+	message := navigation.ConsoleMessage{
+		Level:  consoleLevel(e.Type),
+		Text:   strings.Join(args, " "),
+		Source: "console-api",
+	}
+	if e.StackTrace != nil && len(e.StackTrace.CallFrames) > 0 {
+		frame := e.StackTrace.CallFrames[0]
+		message.URL = frame.URL
+		message.Line = frame.LineNumber + 1
+	}
+
+	cc.Lock()
+	defer cc.Unlock()
+	cc.messages = append(cc.messages, message)
+}
+
+func (cc *consoleCollector) addException(details *proto.RuntimeExceptionDetails) {
+	if details == nil {
+		return
+	}
+	exception := navigation.JSException{
+		Message: details.Text,
+		URL:     details.URL,
+		Line:    details.LineNumber + 1,
+		Column:  details.ColumnNumber + 1,
+	}
+	// the description of the thrown object contains the error message and
+	// the stack, only the first line is kept as the message.
+	if details.Exception != nil && details.Exception.Description != "" {
+		message, _, _ := strings.Cut(details.Exception.Description, "\n")
+		exception.Message = message
+	}
+	if details.StackTrace != nil {
+		for _, frame := range details.StackTrace.CallFrames {
+			exception.StackTrace = append(exception.StackTrace, formatCallFrame(frame))
+		}
+	}
+
+	cc.Lock()
+	defer cc.Unlock()
+	cc.exceptions = append(cc.exceptions, exception)
+}
+
+func (cc *consoleCollector) addLogEntry(entry *proto.LogLogEntry) {
+	if entry == nil {
+		return
+	}
+
+	cc.Lock()
+	defer cc.Unlock()
+
+	if entry.Source == proto.LogLogEntrySourceNetwork {
+		// blocked requests are recorded separately by the request blocker
+		if strings.Contains(entry.Text, blockedByClientError) {
+			return
+		}
+		cc.failed = append(cc.failed, navigation.FailedResource{
+			URL:   entry.URL,
+			Error: strings.TrimPrefix(entry.Text, "Failed to load resource: "),
+		})
+		return
+	}
+	message := navigation.ConsoleMessage{
+		Level:  string(entry.Level),
+		Text:   entry.Text,
+		Source: string(entry.Source),
+		URL:    entry.URL,
+	}
+	if entry.LineNumber != nil {
+		message.Line = *entry.LineNumber + 1
+	}
+	cc.messages = append(cc.messages, message)
+}
+
+// setResponseFields sets the collected events on the response
+func (cc *consoleCollector) setResponseFields(response *navigation.Response) {
+	cc.Lock()
+	defer cc.Unlock()
+	response.ConsoleMessages = cc.messages
+	response.JSExceptions = cc.exceptions
+	response.FailedResources = cc.failed
+}
+
+// consoleLevel normalizes the console api call type to a log level
+func consoleLevel(t proto.RuntimeConsoleAPICalledType) string {
+	switch t {
+	case proto.RuntimeConsoleAPICalledTypeError, proto.RuntimeConsoleAPICalledTypeAssert:
+		return "error"
+	case proto.RuntimeConsoleAPICalledTypeWarning:
+		return "warning"
+	case proto.RuntimeConsoleAPICalledTypeDebug:
+		return "debug"
+	case proto.RuntimeConsoleAPICalledTypeInfo:
+		return "info"
+	default:
+		return string(t)
+	}
+}
+
+// formatRemoteObject returns the textual representation of a console argument
+func formatRemoteObject(obj *proto.RuntimeRemoteObject) string {
+	switch {
+	case obj.Type == proto.RuntimeRemoteObjectTypeString:
+		return obj.Value.Str()
+	case obj.UnserializableValue != "":
+		return string(obj.UnserializableValue)
+	case obj.Description != "":
+		return obj.Description
+	case obj.Value.Nil():
+		return string(obj.Type)
+	default:
+		return obj.Value.JSON("", "")
+	}
+}
+
+// formatCallFrame formats a call frame the way browsers print stack traces
+func formatCallFrame(frame *proto.RuntimeCallFrame) string {
+	function := frame.FunctionName
+	if function == "" {
+		function = "<anonymous>"
+	}
+	return fmt.Sprintf("%s (%s:%d:%d)", function, frame.URL, frame.LineNumber+1, frame.ColumnNumber+1)
+}
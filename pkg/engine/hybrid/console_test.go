@@ -0,0 +1,99 @@
+package hybrid
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/stretchr/testify/require"
+	"github.com/ysmood/gson"
+)
+
+func TestConsoleLevel(t *testing.T) {
+	tests := []struct {
+		callType proto.RuntimeConsoleAPICalledType
+		expected string
+	}{
+		{proto.RuntimeConsoleAPICalledTypeError, "error"},
+		{proto.RuntimeConsoleAPICalledTypeAssert, "error"},
+		{proto.RuntimeConsoleAPICalledTypeWarning, "warning"},
+		{proto.RuntimeConsoleAPICalledTypeDebug, "debug"},
+		{proto.RuntimeConsoleAPICalledTypeInfo, "info"},
+		{proto.RuntimeConsoleAPICalledTypeLog, "log"},
+		{proto.RuntimeConsoleAPICalledTypeTable, "table"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, consoleLevel(tt.callType), "wrong level for %s", tt.callType)
+	}
+}
+
+func TestFormatRemoteObject(t *testing.T) {
+	tests := []struct {
+		name     string
+		obj      *proto.RuntimeRemoteObject
+		expected string
+	}{
+		{name: "string", obj: &proto.RuntimeRemoteObject{Type: proto.RuntimeRemoteObjectTypeString, Value: gson.New("token expired")}, expected: "token expired"},
+		{name: "unserializable", obj: &proto.RuntimeRemoteObject{Type: proto.RuntimeRemoteObjectTypeNumber, UnserializableValue: "NaN"}, expected: "NaN"},
+		{name: "description", obj: &proto.RuntimeRemoteObject{Type: proto.RuntimeRemoteObjectTypeObject, Description: "Error: failed\n    at main.js:1:1"}, expected: "Error: failed\n    at main.js:1:1"},
+		{name: "no value", obj: &proto.RuntimeRemoteObject{Type: proto.RuntimeRemoteObjectTypeUndefined}, expected: "undefined"},
+		{name: "json value", obj: &proto.RuntimeRemoteObject{Type: proto.RuntimeRemoteObjectTypeObject, Value: gson.New(map[string]interface{}{"id": 1})}, expected: `{"id":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, formatRemoteObject(tt.obj))
+		})
+	}
+}
+
+func TestFormatCallFrame(t *testing.T) {
+	frame := &proto.RuntimeCallFrame{FunctionName: "load", URL: "https://example.com/app.js", LineNumber: 9, ColumnNumber: 4}
+	require.Equal(t, "load (https://example.com/app.js:10:5)", formatCallFrame(frame))
+
+	frame.FunctionName = ""
+	require.Equal(t, "<anonymous> (https://example.com/app.js:10:5)", formatCallFrame(frame))
+}
+
+func TestConsoleCollector(t *testing.T) {
+	collector := &consoleCollector{}
+	collector.addConsoleAPICall(&proto.RuntimeConsoleAPICalled{
+		Type: proto.RuntimeConsoleAPICalledTypeWarning,
+		Args: []*proto.RuntimeRemoteObject{
+			{Type: proto.RuntimeRemoteObjectTypeString, Value: gson.New("retrying")},
+			{Type: proto.RuntimeRemoteObjectTypeNumber, Value: gson.New(3)},
+		},
+		StackTrace: &proto.RuntimeStackTrace{CallFrames: []*proto.RuntimeCallFrame{{URL: "https://example.com/app.js", LineNumber: 1}}},
+	})
+	collector.addException(&proto.RuntimeExceptionDetails{
+		Text:         "Uncaught",
+		URL:          "https://example.com/app.js",
+		LineNumber:   4,
+		ColumnNumber: 2,
+		Exception:    &proto.RuntimeRemoteObject{Description: "TypeError: x is undefined\n    at load (app.js:5:3)"},
+		StackTrace:   &proto.RuntimeStackTrace{CallFrames: []*proto.RuntimeCallFrame{{FunctionName: "load", URL: "https://example.com/app.js", LineNumber: 4, ColumnNumber: 2}}},
+	})
+	line := 7
+	collector.addLogEntry(&proto.LogLogEntry{Source: proto.LogLogEntrySourceNetwork, Level: proto.LogLogEntryLevelError, Text: "Failed to load resource: the server responded with a status of 404 ()", URL: "https://example.com/missing.png"})
+	collector.addLogEntry(&proto.LogLogEntry{Source: proto.LogLogEntrySourceNetwork, Level: proto.LogLogEntryLevelError, Text: "Failed to load resource: net::ERR_BLOCKED_BY_CLIENT", URL: "https://ads.example.net/ad.js"})
+	collector.addLogEntry(&proto.LogLogEntry{Source: proto.LogLogEntrySourceSecurity, Level: proto.LogLogEntryLevelError, Text: "mixed content", URL: "https://example.com/", LineNumber: &line})
+	collector.addException(nil)
+	collector.addLogEntry(nil)
+
+	response := &navigation.Response{}
+	collector.setResponseFields(response)
+	require.Equal(t, []navigation.ConsoleMessage{
+		{Level: "warning", Text: "retrying 3", Source: "console-api", URL: "https://example.com/app.js", Line: 2},
+		{Level: "error", Text: "mixed content", Source: "security", URL: "https://example.com/", Line: 8},
+	}, response.ConsoleMessages)
+	require.Equal(t, []navigation.JSException{{
+		Message:    "TypeError: x is undefined",
+		URL:        "https://example.com/app.js",
+		Line:       5,
+		Column:     3,
+		StackTrace: []string{"load (https://example.com/app.js:5:3)"},
+	}}, response.JSExceptions)
+	require.Equal(t, []navigation.FailedResource{{
+		URL:   "https://example.com/missing.png",
+		Error: "the server responded with a status of 404 ()",
+	}}, response.FailedResources)
+}
@@ -0,0 +1,51 @@
+package hybrid
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/katana/pkg/engine/login"
+)
+
+// setJarCookies sets the cookies of the shared jar for the url in the browser, so
+// that the cookies received by the http client are sent by the browser as well.
+// Cookies keep their domain and path so that their scope is unchanged.
+func (c *Crawler) setJarCookies(page *rod.Page, URL string) {
+	parsed, err := url.Parse(URL)
+	if err != nil {
+		return
+	}
+	cookies := c.Jar.ScopedCookies(parsed)
+	if len(cookies) == 0 {
+		return
+	}
+	params := make([]*proto.NetworkCookieParam, 0, len(cookies))
+	for _, cookie := range cookies {
+		params = append(params, login.CookieParam(cookie, URL))
+	}
+	if err := page.SetCookies(params); err != nil {
+		gologger.Debug().Msgf("could not set jar cookies in browser: %s\n", err)
+	}
+}
+
+// storeJarCookies stores the browser cookies of the url in the shared jar, so
+// that the cookies received by the browser are sent by the http client as well.
+func (c *Crawler) storeJarCookies(page *rod.Page, URL string) {
+	parsed, err := url.Parse(URL)
+	if err != nil {
+		return
+	}
+	cookies, err := page.Cookies([]string{URL})
+	if err != nil {
+		gologger.Debug().Msgf("could not get browser cookies: %s\n", err)
+		return
+	}
+	httpCookies := make([]*http.Cookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		httpCookies = append(httpCookies, login.HTTPCookie(cookie))
+	}
+	c.Jar.SetCookies(parsed, httpCookies)
+}
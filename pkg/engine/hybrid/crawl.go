@@ -25,14 +25,14 @@ import (
 	urlutil "github.com/projectdiscovery/utils/url"
 )
 
-func (c *Crawler) navigateRequest(s *common.CrawlSession, request *navigation.Request) (*navigation.Response, error) {
+func (c *Crawler) navigateRequest(s *common.CrawlSession, browser *rod.Browser, request *navigation.Request) (*navigation.Response, error) {
 	depth := request.Depth + 1
 	response := &navigation.Response{
 		Depth:        depth,
 		RootHostname: s.Hostname,
 	}
 
-	page, err := s.Browser.Page(proto.TargetCreateTarget{})
+	page, err := browser.Page(proto.TargetCreateTarget{})
 	if err != nil {
 		return nil, errkit.Wrap(err, "hybrid: could not create target")
 	}
@@ -41,21 +41,43 @@ func (c *Crawler) navigateRequest(s *common.CrawlSession, request *navigation.Re
 			gologger.Error().Msgf("Error closing page: %v\n", err)
 		}
 	}()
+	if err := c.applyProfileToPage(page); err != nil {
+		return nil, err
+	}
 	c.addHeadersToPage(page)
 
-	pageRouter := NewHijack(page)
-	pageRouter.SetPattern(&proto.FetchRequestPattern{
-		URLPattern:   "*",
-		RequestStage: proto.FetchRequestStageResponse,
-	})
+	if err := c.addSessionStorageToPage(page); err != nil {
+		return nil, err
+	}
+
+	reports := &scriptReports{}
+	if err := c.addInitScriptsToPage(page, reports); err != nil {
+		return nil, err
+	}
+
+	console, stopConsole := c.captureConsole(page)
+	defer stopConsole()
+	stopDialogs := c.handleDialogs(page)
+	defer stopDialogs()
+
+	pageRouter := c.newPageRouter(page)
 
 	xhrRequests := []navigation.Request{}
+	replayRequests := []navigation.Request{}
 	go pageRouter.Start(func(e *proto.FetchRequestPaused) error {
+		// request stage events carry neither a response status nor an error
+		if e.ResponseStatusCode == nil && e.ResponseErrorReason == "" {
+			return c.continueOrBlock(s, page, request, e)
+		}
+
 		URL, err := urlutil.Parse(e.Request.URL)
 		if err != nil {
 			return errkit.Wrap(err, "hybrid: could not parse URL")
 		}
 		body, _ := FetchGetResponseBody(page, e)
+		if c.Options.Options.StorageExtraction {
+			c.serverCookies.addFromHeaders(e.Request.URL, e.ResponseHeaders)
+		}
 		headers := make(map[string][]string)
 		for _, h := range e.ResponseHeaders {
 			headers[h.Name] = []string{h.Value}
@@ -143,8 +165,10 @@ func (c *Crawler) navigateRequest(s *common.CrawlSession, request *navigation.Re
 
 			return xhrExtraction && slices.Contains(resourceTypes, e.ResourceType)
 		}
+		// scripts are fetched by the parsers, only xhr and fetch requests are replayed
+		shouldReplay := c.Options.Options.XhrReplay && (e.ResourceType == proto.NetworkResourceTypeXHR || e.ResourceType == proto.NetworkResourceTypeFetch)
 
-		if shouldCapture(c.Options.Options.XhrExtraction) {
+		if shouldCapture(c.Options.Options.XhrExtraction) || shouldReplay {
 			networkReq := navigation.Request{
 				URL:    httpreq.URL.String(),
 				Method: httpreq.Method,
@@ -155,7 +179,12 @@ func (c *Crawler) navigateRequest(s *common.CrawlSession, request *navigation.Re
 			} else {
 				networkReq.Headers = utils.FlattenHeaders(requestHeaders)
 			}
-			xhrRequests = append(xhrRequests, networkReq)
+			if shouldCapture(c.Options.Options.XhrExtraction) {
+				xhrRequests = append(xhrRequests, networkReq)
+			}
+			if shouldReplay {
+				replayRequests = append(replayRequests, networkReq)
+			}
 		}
 
 		// trim trailing /
@@ -185,8 +214,15 @@ func (c *Crawler) navigateRequest(s *common.CrawlSession, request *navigation.Re
 	timeout := time.Duration(c.Options.Options.Timeout) * time.Second
 	page = page.Timeout(timeout)
 
+	// the cookies are shared with the http client fetching the static resources
+	if c.Router != nil {
+		c.setJarCookies(page, request.URL)
+	}
+
 	// wait the page to be fully loaded and becoming idle
 	waitNavigation := page.WaitNavigation(proto.PageLifecycleEventNameFirstMeaningfulPaint)
+	waiter := c.waitStrategies.forURL(request.URL).start(page)
+	defer waiter.stop()
 
 	err = page.Navigate(request.URL)
 	if err != nil {
@@ -198,19 +234,30 @@ func (c *Crawler) navigateRequest(s *common.CrawlSession, request *navigation.Re
 
 	waitNavigation()
 
-	// Wait the page to be stable a duration
-	timeStable := time.Duration(c.Options.Options.TimeStable) * time.Second
-
-	if timeout < timeStable {
-		gologger.Warning().Msgf("timeout is less than time stable, setting time stable to half of timeout to avoid timeout\n")
-		timeStable = timeout / 2
-		gologger.Warning().Msgf("setting time stable to %s\n", timeStable)
+	// Wait the page to be ready according to the url wait strategy
+	if err := waiter.wait(); err != nil {
+		gologger.Warning().Msgf("could not wait for page to be ready: %s\n", err)
 	}
 
-	if err := page.WaitStable(timeStable); err != nil {
-		gologger.Warning().Msgf("could not wait for page to be stable: %s\n", err)
+	c.clickConsent(page)
+	if c.Router != nil {
+		c.storeJarCookies(page, request.URL)
 	}
 
+	// each batch of content loaded by scrolling is parsed as it may be
+	// removed from the page by virtualized lists once scrolled past
+	c.scrollPage(page, func(body string) {
+		if response == nil || response.Resp == nil {
+			return
+		}
+		batch := *response
+		batch.Body = body
+		batch.Reader, _ = goquery.NewDocumentFromReader(strings.NewReader(body))
+		if batch.Reader != nil {
+			c.Enqueue(s.Queue, c.Options.Parser.ParseResponse(&batch)...)
+		}
+	})
+
 	var getDocumentDepth = int(-1)
 	getDocument := &proto.DOMGetDocument{Depth: &getDocumentDepth, Pierce: true}
 	result, err := getDocument.Call(page)
@@ -236,6 +283,9 @@ func (c *Crawler) navigateRequest(s *common.CrawlSession, request *navigation.Re
 	}
 	response.Resp.Request.URL = parsed.URL
 
+	// urls in any attribute of the pierced dom, e.g. custom elements in shadow roots
+	c.Enqueue(s.Queue, extractDOMURLs(result.Root, response)...)
+
 	// Create a copy of intrapolated shadow DOM elements and parse them separately
 	responseCopy := *response
 	responseCopy.Body = builder.String()
@@ -259,11 +309,48 @@ func (c *Crawler) navigateRequest(s *common.CrawlSession, request *navigation.Re
 		return nil, errkit.Wrap(err, "hybrid: could not parse html")
 	}
 
-	response.XhrRequests = xhrRequests
+	if c.Options.Options.StorageExtraction {
+		storage, err := c.extractStorage(page)
+		if err != nil {
+			gologger.Warning().Msgf("%s\n", err)
+		}
+		response.Storage = storage
+		c.enqueueStorageURLs(s, request, response)
+	}
+
+	if c.Options.Options.AutomaticFormFill {
+		c.submitForms(s, browser, request, response)
+	}
+
+	if c.Options.Options.XhrExtraction {
+		response.XhrRequests = xhrRequests
+	}
+	if c.Options.Options.XhrReplay {
+		c.replayXhrRequests(s, request, depth, replayRequests)
+	}
+	response.ScriptData = reports.values()
+	console.setResponseFields(response)
 
 	return response, nil
 }
 
+// newPageRouter returns a hijack pausing the responses of the page, and its
+// requests before being sent when they may be blocked.
+func (c *Crawler) newPageRouter(page *rod.Page) *Hijack {
+	pageRouter := NewHijack(page)
+	pageRouter.SetPattern(&proto.FetchRequestPattern{
+		URLPattern:   "*",
+		RequestStage: proto.FetchRequestStageResponse,
+	})
+	if c.blocker != nil {
+		pageRouter.AddPattern(&proto.FetchRequestPattern{
+			URLPattern:   "*",
+			RequestStage: proto.FetchRequestStageRequest,
+		})
+	}
+	return pageRouter
+}
+
 func (c *Crawler) addHeadersToPage(page *rod.Page) {
 	if len(c.Headers) == 0 {
 		return
@@ -319,7 +406,8 @@ func traverseDOMNode(node *proto.DOMNode, builder *strings.Builder) {
 }
 
 const (
-	elementNode = 1
+	elementNode  = 1
+	documentNode = 9
 )
 
 var knownElements = map[string]struct{}{
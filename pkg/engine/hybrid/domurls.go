@@ -0,0 +1,171 @@
+package hybrid
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+)
+
+// maxDOMURLLength is the maximum length of an attribute value considered as url
+const maxDOMURLLength = 2048
+
+// domURLAttributes are the attributes whose value is a url, relative values
+// are accepted for them while other attributes need url-like values.
+var domURLAttributes = map[string]struct{}{
+	"href": {}, "src": {}, "action": {}, "formaction": {}, "xlink:href": {}, "poster": {}, "data": {},
+	"cite": {}, "background": {}, "codebase": {}, "longdesc": {}, "lowsrc": {}, "dynsrc": {},
+	"manifest": {}, "ping": {}, "icon": {}, "archive": {}, "usemap": {}, "itemid": {},
+}
+
+// domSrcsetAttributes are the attributes holding a list of candidate urls
+var domSrcsetAttributes = map[string]struct{}{
+	"srcset": {}, "imagesrcset": {},
+}
+
+// domIgnoredAttributes are the attributes which never contain urls, inline
+// event handlers and styles are left to their respective parsers.
+var domIgnoredAttributes = map[string]struct{}{
+	"class": {}, "id": {}, "style": {}, "name": {}, "type": {}, "rel": {}, "lang": {}, "dir": {},
+	"role": {}, "width": {}, "height": {}, "alt": {}, "for": {}, "charset": {}, "integrity": {},
+	"crossorigin": {}, "nonce": {}, "xmlns": {}, "viewbox": {}, "d": {}, "fill": {}, "stroke": {},
+}
+
+// domJunkPrefixes are the prefixes of values which can't be crawled
+var domJunkPrefixes = []string{"#", "javascript:", "data:", "mailto:", "tel:", "about:", "blob:", "sms:", "file:"}
+
+// domTemplateMarkers are found in unrendered client side templates
+var domTemplateMarkers = []string{"{{", "}}", "${", "<%", "%>", "[[", "]]"}
+
+// extractDOMURLs walks the pierced dom tree of the page, including shadow roots,
+// iframes and templates, and returns the urls found in any attribute of any
+// element. Each request records the element and attribute it was found in.
+func extractDOMURLs(root *proto.DOMNode, response *navigation.Response) []*navigation.Request {
+	extractor := &domURLExtractor{
+		response: response,
+		source:   response.Resp.Request.URL.String(),
+		seen:     make(map[string]struct{}),
+	}
+	extractor.walk(root, response.Resp.Request.URL)
+	return extractor.requests
+}
+
+type domURLExtractor struct {
+	response *navigation.Response
+	source   string
+	seen     map[string]struct{}
+	requests []*navigation.Request
+}
+
+func (e *domURLExtractor) walk(node *proto.DOMNode, base *url.URL) {
+	// documents of iframes and pages with a <base> element have their own base url
+	if node.NodeType == documentNode && node.BaseURL != "" {
+		if parsed, err := url.Parse(node.BaseURL); err == nil {
+			base = parsed
+		}
+	}
+	if node.NodeType == elementNode {
+		for i := 0; i+1 < len(node.Attributes); i += 2 {
+			e.extractAttribute(node.LocalName, strings.ToLower(node.Attributes[i]), node.Attributes[i+1], base)
+		}
+	}
+
+	if node.TemplateContent != nil {
+		e.walk(node.TemplateContent, base)
+	}
+	if node.ContentDocument != nil {
+		e.walk(node.ContentDocument, base)
+	}
+	for _, children := range node.Children {
+		e.walk(children, base)
+	}
+	for _, shadow := range node.ShadowRoots {
+		e.walk(shadow, base)
+	}
+	for _, pseudo := range node.PseudoElements {
+		e.walk(pseudo, base)
+	}
+}
+
+func (e *domURLExtractor) extractAttribute(tag, attribute, value string, base *url.URL) {
+	value = strings.TrimSpace(value)
+	if value == "" || len(value) > maxDOMURLLength {
+		return
+	}
+	if _, ok := domIgnoredAttributes[attribute]; ok || strings.HasPrefix(attribute, "on") || strings.HasPrefix(attribute, "aria-") {
+		return
+	}
+
+	switch {
+	case isDOMURLAttribute(attribute):
+		e.add(tag, attribute, value, base)
+	case isDOMSrcsetAttribute(attribute):
+		for _, candidate := range strings.Split(value, ",") {
+			if fields := strings.Fields(candidate); len(fields) > 0 {
+				e.add(tag, attribute, fields[0], base)
+			}
+		}
+	default:
+		// other attributes, e.g. data-* or custom element properties,
+		// only contribute url-like values or urls embedded in json
+		for _, item := range utils.ExtractURLLikeValues(value) {
+			e.add(tag, attribute, item, base)
+		}
+	}
+}
+
+func (e *domURLExtractor) add(tag, attribute, value string, base *url.URL) {
+	if isJunkDOMValue(value) {
+		return
+	}
+	resolved, err := base.Parse(value)
+	if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+		return
+	}
+	resolved.Fragment = ""
+	URL := resolved.String()
+	if _, ok := e.seen[URL]; ok {
+		return
+	}
+	e.seen[URL] = struct{}{}
+
+	e.requests = append(e.requests, &navigation.Request{
+		Method:       http.MethodGet,
+		URL:          URL,
+		RootHostname: e.response.RootHostname,
+		Depth:        e.response.Depth,
+		Source:       e.source,
+		Tag:          tag,
+		Attribute:    attribute,
+	})
+}
+
+func isDOMURLAttribute(attribute string) bool {
+	_, ok := domURLAttributes[attribute]
+	return ok
+}
+
+func isDOMSrcsetAttribute(attribute string) bool {
+	_, ok := domSrcsetAttributes[attribute]
+	return ok
+}
+
+// isJunkDOMValue returns true if the value can't be a crawlable url, e.g.
+// script urls, unrendered templates or values with whitespaces.
+func isJunkDOMValue(value string) bool {
+	lower := strings.ToLower(value)
+	for _, prefix := range domJunkPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	for _, marker := range domTemplateMarkers {
+		if strings.Contains(value, marker) {
+			return true
+		}
+	}
+	return strings.ContainsAny(value, " \t\r\n<>\"'`")
+}
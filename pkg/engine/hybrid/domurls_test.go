@@ -0,0 +1,58 @@
+package hybrid
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractDOMURLs(t *testing.T) {
+	element := func(name string, attributes ...string) *proto.DOMNode {
+		return &proto.DOMNode{NodeType: elementNode, LocalName: name, Attributes: attributes}
+	}
+
+	widget := element("x-product-card", "data-endpoint", `{"detail":"/api/products/1","id":1}`, "class", "https://example.com/not-a-url")
+	widget.ShadowRoots = []*proto.DOMNode{{Children: []*proto.DOMNode{
+		element("a", "href", "reviews?page=2", "onclick", "location='/ignored'"),
+		element("image", "xlink:href", "/img/product.png"),
+	}}}
+	iframe := element("iframe", "src", "/frame")
+	iframe.ContentDocument = &proto.DOMNode{NodeType: documentNode, BaseURL: "https://cdn.example.com/frame/", Children: []*proto.DOMNode{
+		element("img", "srcset", "small.png 1x, large.png 2x"),
+	}}
+	root := &proto.DOMNode{NodeType: documentNode, Children: []*proto.DOMNode{
+		element("a", "href", "javascript:void(0)"),
+		element("a", "href", "/items/{{ item.id }}"),
+		element("a", "href", "#top"),
+		element("my-app", "config-url", "https://api.example.com/config", "title", "some title"),
+		widget,
+		iframe,
+	}}
+
+	requestURL, _ := url.Parse("https://example.com/shop/")
+	response := &navigation.Response{
+		Resp:         &http.Response{Request: &http.Request{URL: requestURL}},
+		Depth:        2,
+		RootHostname: "example.com",
+	}
+
+	found := make(map[string]string)
+	for _, request := range extractDOMURLs(root, response) {
+		require.Equal(t, 2, request.Depth)
+		require.Equal(t, "https://example.com/shop/", request.Source)
+		found[request.URL] = request.Tag + ":" + request.Attribute
+	}
+	require.Equal(t, map[string]string{
+		"https://api.example.com/config":          "my-app:config-url",
+		"https://example.com/api/products/1":      "x-product-card:data-endpoint",
+		"https://example.com/shop/reviews?page=2": "a:href",
+		"https://example.com/img/product.png":     "image:xlink:href",
+		"https://example.com/frame":               "iframe:src",
+		"https://cdn.example.com/frame/small.png": "img:srcset",
+		"https://cdn.example.com/frame/large.png": "img:srcset",
+	}, found)
+}
@@ -0,0 +1,263 @@
+package hybrid
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/katana/pkg/engine/common"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+	"github.com/projectdiscovery/utils/errkit"
+)
+
+// requestIdleDuration is the duration without network activity after which the
+// requests triggered by a page interaction (e.g. form submission) are considered complete.
+const requestIdleDuration = 500 * time.Millisecond
+
+// fillFormJS fills the form with the signature with the values, dispatching the input
+// and change events frameworks listen to, and clicks the submit control.
+// Forms are looked up by signature as their position in the document may differ
+// from the parsed one, e.g. for forms added or removed by the page scripts.
+// Values are set through the native setter so that frameworks tracking
+// the value property (e.g. react) pick up the change.
+const fillFormJS = `(signature, values) => {
+	const formSignature = (form) => {
+		const method = (form.getAttribute("method") || "GET").toUpperCase();
+		const action = form.getAttribute("action") || "";
+		let actionURL = "";
+		if (!action.startsWith("#")) {
+			try { actionURL = new URL(action, location.href).href; } catch (e) {}
+		}
+		if (!actionURL) actionURL = location.href;
+		actionURL = actionURL.split("#")[0].split("?")[0];
+		const fields = Array.from(form.querySelectorAll("input, select, textarea"))
+			.map((el) => el.getAttribute("name"))
+			.filter(Boolean)
+			.sort();
+		return method + " " + actionURL + " " + fields.join(",");
+	};
+	const form = Array.from(document.forms).find((form) => formSignature(form) === signature);
+	if (!form) return false;
+	const skip = ["hidden", "file", "submit", "button", "image", "reset"];
+	for (const el of form.elements) {
+		if (!el.name || el.disabled || !(el.name in values) || skip.includes(el.type)) continue;
+		const value = values[el.name];
+		if (el.type === "checkbox" || el.type === "radio") {
+			if (el.type === "radio" && el.value !== value) continue;
+			el.checked = true;
+		} else {
+			const descriptor = Object.getOwnPropertyDescriptor(Object.getPrototypeOf(el), "value");
+			if (descriptor && descriptor.set) descriptor.set.call(el, value); else el.value = value;
+		}
+		el.dispatchEvent(new Event("input", {bubbles: true}));
+		el.dispatchEvent(new Event("change", {bubbles: true}));
+	}
+	const submit = form.querySelector("[type=submit], button:not([type]), input[type=image]");
+	if (submit) submit.click();
+	else if (form.requestSubmit) form.requestSubmit();
+	else form.submit();
+	return true;
+}`
+
+// formSubmissionTypes are the resource types captured after a form submission
+var formSubmissionTypes = []proto.NetworkResourceType{
+	proto.NetworkResourceTypeDocument,
+	proto.NetworkResourceTypeXHR,
+	proto.NetworkResourceTypeFetch,
+}
+
+// submitForms fills and submits each form of the response inside the browser,
+// forms already submitted with the same signature are skipped.
+func (c *Crawler) submitForms(s *common.CrawlSession, browser *rod.Browser, request *navigation.Request, response *navigation.Response) {
+	response.Reader.Find("form").Each(func(_ int, form *goquery.Selection) {
+		// template contents are not part of the document
+		if form.Closest("template").Length() > 0 {
+			return
+		}
+		signature := formSignature(response, form)
+		if _, submitted := c.submittedForms.LoadOrStore(signature, struct{}{}); submitted {
+			return
+		}
+		requests, err := c.submitForm(s, browser, request, signature, formValues(form))
+		if err != nil {
+			gologger.Warning().Msgf("Could not submit form %s of %s: %s\n", signature, request.URL, err)
+			return
+		}
+		for _, req := range requests {
+			req.Depth = response.Depth
+			req.RootHostname = s.Hostname
+			req.Source = request.URL
+		}
+		c.recordFormRequests(s, requests)
+	})
+}
+
+// submitForm loads the page in a new target, submits the form with the signature
+// and returns the navigations and xhr requests triggered by the submission.
+// The page is set up like the crawled ones, with the init scripts and the blocker.
+func (c *Crawler) submitForm(s *common.CrawlSession, browser *rod.Browser, request *navigation.Request, signature string, values map[string]string) ([]*navigation.Request, error) {
+	page, err := browser.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		return nil, errkit.Wrap(err, "hybrid: could not create target")
+	}
+	defer func() {
+		if err := page.Close(); err != nil {
+			gologger.Error().Msgf("Error closing page: %v\n", err)
+		}
+	}()
+	if err := c.applyProfileToPage(page); err != nil {
+		return nil, err
+	}
+	c.addHeadersToPage(page)
+	if err := c.addSessionStorageToPage(page); err != nil {
+		return nil, err
+	}
+	if err := c.addInitScriptsToPage(page, &scriptReports{}); err != nil {
+		return nil, err
+	}
+	stopDialogs := c.handleDialogs(page)
+	defer stopDialogs()
+
+	pageRouter := c.newPageRouter(page)
+	go pageRouter.Start(func(e *proto.FetchRequestPaused) error {
+		// request stage events carry neither a response status nor an error
+		if e.ResponseStatusCode == nil && e.ResponseErrorReason == "" {
+			return c.continueOrBlock(s, page, request, e)
+		}
+		if c.Options.Options.StorageExtraction {
+			c.serverCookies.addFromHeaders(e.Request.URL, e.ResponseHeaders)
+		}
+		return FetchContinueRequest(page, e)
+	})() //nolint
+	defer func() {
+		if err := pageRouter.Stop(); err != nil {
+			gologger.Warning().Msgf("%s\n", err)
+		}
+	}()
+
+	timeout := time.Duration(c.Options.Options.Timeout) * time.Second
+	page = page.Timeout(timeout)
+	if c.Router != nil {
+		c.setJarCookies(page, request.URL)
+	}
+	waiter := c.waitStrategies.forURL(request.URL).start(page)
+	defer waiter.stop()
+	if err := page.Navigate(request.URL); err != nil {
+		return nil, errkit.Wrap(err, "hybrid: could not navigate target")
+	}
+	if err := waiter.wait(); err != nil {
+		gologger.Debug().Msgf("could not wait for page to be ready: %s\n", err)
+	}
+	c.clickConsent(page)
+
+	var (
+		mutex    sync.Mutex
+		requests []*navigation.Request
+	)
+	eventsPage, cancel := page.WithCancel()
+	defer cancel()
+	wait := eventsPage.EachEvent(func(e *proto.NetworkRequestWillBeSent) {
+		if !slices.Contains(formSubmissionTypes, e.Type) {
+			return
+		}
+		req := &navigation.Request{
+			Method:    e.Request.Method,
+			URL:       e.Request.URL,
+			Body:      e.Request.PostData,
+			Headers:   make(map[string]string, len(e.Request.Headers)),
+			Tag:       "form",
+			Attribute: strings.ToLower(string(e.Type)),
+		}
+		for name, value := range e.Request.Headers {
+			req.Headers[name] = value.Str()
+		}
+		mutex.Lock()
+		requests = append(requests, req)
+		mutex.Unlock()
+	})
+	go wait()
+
+	waitIdle := page.WaitRequestIdle(requestIdleDuration, nil, nil, nil)
+	result, err := page.Eval(fillFormJS, signature, values)
+	// a navigation triggered by the submission may destroy the evaluation context
+	if err == nil && !result.Value.Bool() {
+		return nil, errkit.New("hybrid: form not found in page")
+	}
+	waitIdle()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	return requests, nil
+}
+
+// recordFormRequests queues the GET requests triggered by form submissions
+// and writes the other ones to the output as they can't be navigated.
+func (c *Crawler) recordFormRequests(s *common.CrawlSession, requests []*navigation.Request) {
+	for _, req := range requests {
+		if req.Method == http.MethodGet {
+			c.Enqueue(s.Queue, req)
+			continue
+		}
+		if !c.Options.UniqueFilter.UniqueURL(req.Method + ":" + req.URL + ":" + req.Body) {
+			continue
+		}
+		if !c.ValidateScope(req.URL, s.Hostname) && !c.Options.Options.DisplayOutScope {
+			continue
+		}
+		c.Output(req, nil, nil)
+	}
+}
+
+// formValues returns the suggested values for the fields of the form
+func formValues(form *goquery.Selection) map[string]string {
+	formFields := []interface{}{}
+	form.Find("input, select, textarea").Each(func(index int, item *goquery.Selection) {
+		if len(item.Nodes) == 0 {
+			return
+		}
+		formFields = append(formFields, utils.ConvertGoquerySelectionToFormField(item))
+	})
+
+	values := make(map[string]string)
+	dataMap := utils.FormFillSuggestions(formFields)
+	dataMap.Iterate(func(key, value string) bool {
+		if key != "" {
+			values[key] = value
+		}
+		return true
+	})
+	return values
+}
+
+// formSignature identifies a form by its method, action and field names
+func formSignature(response *navigation.Response, form *goquery.Selection) string {
+	action, _ := form.Attr("action")
+	method, _ := form.Attr("method")
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var fields []string
+	form.Find("input, select, textarea").Each(func(_ int, item *goquery.Selection) {
+		if name, ok := item.Attr("name"); ok && name != "" {
+			fields = append(fields, name)
+		}
+	})
+	sort.Strings(fields)
+
+	actionURL := response.AbsoluteURL(action)
+	if actionURL == "" {
+		actionURL = response.Resp.Request.URL.String()
+	}
+	actionURL, _, _ = strings.Cut(actionURL, "#")
+	actionURL, _, _ = strings.Cut(actionURL, "?")
+	return strings.ToUpper(method) + " " + actionURL + " " + strings.Join(fields, ",")
+}
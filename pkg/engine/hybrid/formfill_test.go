@@ -0,0 +1,83 @@
+package hybrid
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func parseForm(t *testing.T, html string) *goquery.Selection {
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	require.NoError(t, err)
+	return document.Find("form").First()
+}
+
+func TestFormSignature(t *testing.T) {
+	requestURL, _ := url.Parse("https://example.com/account/settings?tab=1#profile")
+	response := &navigation.Response{
+		Resp: &http.Response{Request: &http.Request{URL: requestURL}},
+	}
+
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "relative action",
+			html:     `<form action="update?x=1" method="post"><input name="email"><input name="name"></form>`,
+			expected: "POST https://example.com/account/update email,name",
+		},
+		{
+			name:     "default method and action",
+			html:     `<form><input name="q"></form>`,
+			expected: "GET https://example.com/account/settings q",
+		},
+		{
+			name:     "fragment action",
+			html:     `<form action="#top"><input name="q"></form>`,
+			expected: "GET https://example.com/account/settings q",
+		},
+		{
+			name:     "sorted named fields",
+			html:     `<form action="/search"><textarea name="z"></textarea><select name="b"></select><input name="a"><input type="submit"></form>`,
+			expected: "GET https://example.com/search a,b,z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, formSignature(response, parseForm(t, tt.html)))
+		})
+	}
+}
+
+func TestFormValues(t *testing.T) {
+	form := parseForm(t, `<form>
+		<input type="email" name="email">
+		<input type="password" name="password">
+		<input type="text" name="city" placeholder="Paris">
+		<input type="hidden" name="csrf" value="token">
+		<input type="checkbox" name="remember" value="yes">
+		<input type="number" name="count" min="2" max="10" step="3">
+		<input type="text">
+		<select name="country"><option value="fr">France</option><option value="de" selected>Germany</option></select>
+		<textarea name="comment"></textarea>
+	</form>`)
+
+	require.Equal(t, map[string]string{
+		"email":    utils.FormData.Email,
+		"password": utils.FormData.Password,
+		"city":     "Paris",
+		"csrf":     "token",
+		"remember": "yes",
+		"count":    "5",
+		"country":  "de",
+		"comment":  utils.FormData.Placeholder,
+	}, formValues(form))
+}
@@ -33,6 +33,15 @@ func (h *Hijack) SetPattern(pattern *proto.FetchRequestPattern) {
 	}
 }
 
+// AddPattern adds a pattern to the already set patterns
+func (h *Hijack) AddPattern(pattern *proto.FetchRequestPattern) {
+	if h.enable == nil {
+		h.SetPattern(pattern)
+		return
+	}
+	h.enable.Patterns = append(h.enable.Patterns, pattern)
+}
+
 // Start hijack.
 func (h *Hijack) Start(handler HijackHandler) func() error {
 	if h.enable == nil {
@@ -95,3 +104,12 @@ func FetchContinueRequest(page *rod.Page, e *proto.FetchRequestPaused) error {
 	}
 	return m.Call(page)
 }
+
+// FetchFailRequest fails the request with the reason
+func FetchFailRequest(page *rod.Page, e *proto.FetchRequestPaused, reason proto.NetworkErrorReason) error {
+	m := proto.FetchFailRequest{
+		RequestID:   e.RequestID,
+		ErrorReason: reason,
+	}
+	return m.Call(page)
+}
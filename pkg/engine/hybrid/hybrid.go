@@ -1,24 +1,21 @@
 package hybrid
 
 import (
-	"fmt"
 	"os"
+	"sync"
 
-	"github.com/go-rod/rod"
-	"github.com/go-rod/rod/lib/launcher"
-	"github.com/go-rod/rod/lib/launcher/flags"
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/katana/pkg/engine/common"
+	"github.com/projectdiscovery/katana/pkg/engine/profile"
 	"github.com/projectdiscovery/katana/pkg/types"
 	"github.com/projectdiscovery/utils/errkit"
-	urlutil "github.com/projectdiscovery/utils/url"
 )
 
 // Crawler is a standard crawler instance
 type Crawler struct {
 	*common.Shared
 
-	browser *rod.Browser
+	pool *browserPool
 	// TODO: Remove the Chrome PID kill code in favor of using Leakless(true).
 	// This change will be made if there are no complaints about zombie Chrome processes.
 	// References:
@@ -26,6 +23,26 @@ type Crawler struct {
 	// https://github.com/projectdiscovery/httpx/issues/1425
 	// previousPIDs map[int32]struct{} // track already running PIDs
 	tempDir string
+	// initScripts are evaluated on every new document before page scripts
+	initScripts []string
+	// waitStrategies selects how to wait for a page to be ready
+	waitStrategies *waitStrategies
+	// dialogs answers the javascript dialogs of the pages
+	dialogs *dialogHandler
+	// consentSelectors are the consent manager buttons clicked on the pages
+	consentSelectors []string
+	// profile is the device, locale and stealth profile emulated by the pages
+	profile *profile.Profile
+	// blocker fails the page requests matching the blocking options
+	blocker *requestBlocker
+	// serverCookies tracks the cookies set by servers when extracting storage
+	serverCookies *serverCookies
+	// requeued tracks the requests re-queued after a browser restart
+	requeued sync.Map
+	// replayedXhrs tracks the method, url and body shape of the replayed xhr requests
+	replayedXhrs sync.Map
+	// submittedForms tracks the signatures of the forms submitted in the browser
+	submittedForms sync.Map
 }
 
 // New returns a new standard crawler instance
@@ -43,52 +60,69 @@ func New(options *types.CrawlerOptions) (*Crawler, error) {
 
 	// previousPIDs := processutil.FindProcesses(processutil.IsChromeProcess)
 
-	var launcherURL string
-	var chromeLauncher *launcher.Launcher
-
-	if options.Options.ChromeWSUrl != "" {
-		launcherURL = options.Options.ChromeWSUrl
-	} else {
-		// create new chrome launcher instance
-		chromeLauncher, err = buildChromeLauncher(options, dataStore)
+	var browserProfile *profile.Profile
+	if options.Options.HeadlessProfile != "" {
+		browserProfile, err = profile.Get(options.Options.HeadlessProfile)
 		if err != nil {
-			return nil, err
+			return nil, errkit.Wrap(err, "hybrid")
 		}
+	}
 
-		// launch chrome headless process
-		launcherURL, err = chromeLauncher.Launch()
-		if err != nil {
-			return nil, err
-		}
+	shared, err := common.NewShared(options)
+	if err != nil {
+		return nil, errkit.Wrap(err, "hybrid")
 	}
 
-	browser := rod.New().ControlURL(launcherURL)
-	if browserErr := browser.Connect(); browserErr != nil {
-		return nil, errkit.Wrap(browserErr, fmt.Sprintf("hybrid: failed to connect to chrome instance at %s", launcherURL))
+	initScripts, err := loadInitScripts(options.Options.HeadlessInitScripts)
+	if err != nil {
+		return nil, err
 	}
 
-	// create a new browser instance (default to incognito mode)
-	if !options.Options.HeadlessNoIncognito {
-		incognito, err := browser.Incognito()
-		if err != nil {
-			if chromeLauncher != nil {
-				chromeLauncher.Kill()
-			}
-			return nil, errkit.Wrap(err, "hybrid: failed to create incognito browser")
-		}
-		browser = incognito
+	blocker, err := newRequestBlocker(options.Options)
+	if err != nil {
+		return nil, err
 	}
 
-	shared, err := common.NewShared(options)
+	waitStrategies, err := newWaitStrategies(options.Options)
 	if err != nil {
-		return nil, errkit.Wrap(err, "hybrid")
+		return nil, err
+	}
+
+	dialogs, err := newDialogHandler(options.Options.HeadlessDialog)
+	if err != nil {
+		return nil, err
+	}
+
+	consentSelectors, err := loadConsentSelectors(options.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	// the browsers are launched once the options are validated
+	pool, err := newBrowserPool(options, browserProfile, dataStore)
+	if err != nil {
+		return nil, err
 	}
 
 	crawler := &Crawler{
 		Shared:  shared,
-		browser: browser,
+		pool:    pool,
+		profile: browserProfile,
 		// previousPIDs: previousPIDs,
-		tempDir: dataStore,
+		tempDir:          dataStore,
+		initScripts:      initScripts,
+		blocker:          blocker,
+		waitStrategies:   waitStrategies,
+		dialogs:          dialogs,
+		consentSelectors: consentSelectors,
+	}
+	if options.Options.StorageExtraction {
+		crawler.serverCookies = newServerCookies()
+	}
+
+	if err := crawler.Authenticate(crawler.executeLogin); err != nil {
+		pool.close()
+		return nil, errkit.Wrap(err, "hybrid")
 	}
 
 	return crawler, nil
@@ -96,6 +130,8 @@ func New(options *types.CrawlerOptions) (*Crawler, error) {
 
 // Close closes the crawler process
 func (c *Crawler) Close() error {
+	// the browsers are released before their data dirs are removed
+	c.pool.close()
 	if c.Options.Options.ChromeDataDir == "" {
 		if err := os.RemoveAll(c.tempDir); err != nil {
 			return err
@@ -111,68 +147,16 @@ func (c *Crawler) Crawl(rootURL string) error {
 	if err != nil {
 		return errkit.Wrap(err, "hybrid")
 	}
-	crawlSession.Browser = c.browser
+	// the session is assigned to the least loaded browser of the pool
+	instance := c.pool.acquire()
+	defer c.pool.release(instance)
+	crawlSession.Browser, _ = instance.current()
 
 	defer crawlSession.CancelFunc()
 
 	gologger.Info().Msgf("Started headless crawling for => %v", rootURL)
-	if err := c.Do(crawlSession, c.navigateRequest); err != nil {
+	if err := c.Do(crawlSession, c.Route(c.navigateRequestWithRecovery(instance))); err != nil {
 		return errkit.Wrap(err, "hybrid")
 	}
 	return nil
 }
-
-// buildChromeLauncher builds a new chrome launcher instance
-func buildChromeLauncher(options *types.CrawlerOptions, dataStore string) (*launcher.Launcher, error) {
-	chromeLauncher := launcher.New().
-		Leakless(true).
-		Set("disable-gpu", "true").
-		Set("ignore-certificate-errors", "true").
-		Set("ignore-certificate-errors", "1").
-		Set("disable-crash-reporter", "true").
-		Set("disable-notifications", "true").
-		Set("hide-scrollbars", "true").
-		Set("window-size", fmt.Sprintf("%d,%d", 1080, 1920)).
-		Set("mute-audio", "true").
-		Delete("use-mock-keychain").
-		UserDataDir(dataStore)
-
-	if options.Options.UseInstalledChrome {
-		if options.Options.SystemChromePath != "" {
-			chromeLauncher.Bin(options.Options.SystemChromePath)
-		} else {
-			if chromePath, hasChrome := launcher.LookPath(); hasChrome {
-				chromeLauncher.Bin(chromePath)
-			} else {
-				return nil, errkit.New("hybrid: the chrome browser is not installed")
-			}
-		}
-	}
-	if options.Options.SystemChromePath != "" {
-		chromeLauncher.Bin(options.Options.SystemChromePath)
-	}
-
-	if options.Options.ShowBrowser {
-		chromeLauncher = chromeLauncher.Headless(false)
-	} else {
-		chromeLauncher = chromeLauncher.Headless(true)
-	}
-
-	if options.Options.HeadlessNoSandbox {
-		chromeLauncher.Set("no-sandbox", "true")
-	}
-
-	if options.Options.Proxy != "" && options.Options.Headless {
-		proxyURL, err := urlutil.Parse(options.Options.Proxy)
-		if err != nil {
-			return nil, err
-		}
-		chromeLauncher.Set("proxy-server", proxyURL.String())
-	}
-
-	for k, v := range options.Options.ParseHeadlessOptionalArguments() {
-		chromeLauncher.Set(flags.Flag(k), v)
-	}
-
-	return chromeLauncher, nil
-}
@@ -0,0 +1,71 @@
+package hybrid
+
+import (
+	"os"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/projectdiscovery/utils/errkit"
+	"github.com/ysmood/gson"
+)
+
+// initScriptReportFunc is the name of the function exposed on the window
+// object which init scripts can use to send structured data back to katana.
+//
+//	window.katanaReport({token: localStorage.getItem("jwt")})
+const initScriptReportFunc = "katanaReport"
+
+// loadInitScripts reads the init script files from disk
+func loadInitScripts(files []string) ([]string, error) {
+	scripts := make([]string, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errkit.Wrap(err, "hybrid: could not read init script")
+		}
+		scripts = append(scripts, string(data))
+	}
+	return scripts, nil
+}
+
+// scriptReports collects the data reported by init scripts for a page
+type scriptReports struct {
+	sync.Mutex
+	data []interface{}
+}
+
+func (r *scriptReports) add(value interface{}) {
+	r.Lock()
+	defer r.Unlock()
+	r.data = append(r.data, value)
+}
+
+func (r *scriptReports) values() []interface{} {
+	r.Lock()
+	defer r.Unlock()
+	return r.data
+}
+
+// addInitScriptsToPage registers the user provided init scripts on the page so that
+// they are evaluated in every frame before any of the page scripts are executed.
+func (c *Crawler) addInitScriptsToPage(page *rod.Page, reports *scriptReports) error {
+	if len(c.initScripts) == 0 {
+		return nil
+	}
+
+	// the report function is exposed first so that it is available to all the init scripts
+	_, err := page.Expose(initScriptReportFunc, func(data gson.JSON) (interface{}, error) {
+		reports.add(data.Val())
+		return nil, nil
+	})
+	if err != nil {
+		return errkit.Wrap(err, "hybrid: could not expose report function")
+	}
+
+	for _, script := range c.initScripts {
+		if _, err := page.EvalOnNewDocument(script); err != nil {
+			return errkit.Wrap(err, "hybrid: could not add init script")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package hybrid
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadInitScripts(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.js")
+	second := filepath.Join(dir, "second.js")
+	require.Nil(t, os.WriteFile(first, []byte("window.first = true;"), 0o600))
+	require.Nil(t, os.WriteFile(second, []byte("katanaReport({token: localStorage.getItem('jwt')});"), 0o600))
+
+	tests := []struct {
+		name     string
+		files    []string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "no files", files: nil, expected: []string{}},
+		{name: "files in order", files: []string{second, first}, expected: []string{"katanaReport({token: localStorage.getItem('jwt')});", "window.first = true;"}},
+		{name: "missing file", files: []string{first, filepath.Join(dir, "missing.js")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scripts, err := loadInitScripts(tt.files)
+			if tt.wantErr {
+				require.NotNil(t, err, "could not get error for missing init script")
+				return
+			}
+			require.Nil(t, err, "could not load init scripts")
+			require.Equal(t, tt.expected, scripts)
+		})
+	}
+}
+
+func TestScriptReports(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []interface{}
+	}{
+		{name: "empty", values: nil},
+		{name: "values in order", values: []interface{}{map[string]interface{}{"token": "abc"}, "value", 1.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reports := &scriptReports{}
+			for _, value := range tt.values {
+				reports.add(value)
+			}
+			require.Equal(t, tt.values, reports.values())
+		})
+	}
+
+	// reports are added concurrently by the exposed function of every frame
+	reports := &scriptReports{}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reports.add(i)
+		}()
+	}
+	wg.Wait()
+	require.Len(t, reports.values(), 10)
+}
@@ -0,0 +1,297 @@
+package hybrid
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/katana/pkg/engine/common"
+	"github.com/projectdiscovery/katana/pkg/engine/login"
+	"github.com/projectdiscovery/katana/pkg/engine/profile"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/types"
+	"github.com/projectdiscovery/utils/errkit"
+)
+
+const (
+	// healthCheckTimeout is the timeout of the browser health check
+	healthCheckTimeout = 5 * time.Second
+	// cookieSnapshotInterval is the minimum interval between two snapshots
+	// of the browser cookies restored after a relaunch.
+	cookieSnapshotInterval = 5 * time.Second
+)
+
+// browserInstance is a chrome browser which is relaunched, or reconnected
+// to when using a remote browser, if it crashes or disconnects.
+type browserInstance struct {
+	options   *types.CrawlerOptions
+	profile   *profile.Profile
+	dataStore string
+	wsURL     string
+
+	mutex    sync.RWMutex
+	launcher *launcher.Launcher
+	// connection is the devtools connection to a remote browser, closed
+	// on release without closing the browser itself.
+	connection *cdp.WebSocket
+	root       *rod.Browser
+	browser    *rod.Browser
+	// generation is incremented on every restart so that concurrent
+	// failures of the same browser only trigger a single restart.
+	generation int
+	restarts   int
+
+	// slots limits the concurrent pages of the instance, nil if unlimited
+	slots chan struct{}
+
+	cookiesMutex sync.Mutex
+	cookies      []*proto.NetworkCookie
+	lastSnapshot time.Time
+
+	// launchBrowser and isAlive start and health check the browser,
+	// they default to launch and alive.
+	launchBrowser func() error
+	isAlive       func(browser *rod.Browser) bool
+}
+
+// newBrowserInstance launches a local chrome or connects to the one at wsURL
+func newBrowserInstance(options *types.CrawlerOptions, browserProfile *profile.Profile, dataStore, wsURL string) (*browserInstance, error) {
+	instance := &browserInstance{
+		options:   options,
+		profile:   browserProfile,
+		dataStore: dataStore,
+		wsURL:     wsURL,
+	}
+	instance.launchBrowser = instance.launch
+	instance.isAlive = instance.alive
+	if concurrency := options.Options.HeadlessInstanceConcurrency; concurrency > 0 {
+		instance.slots = make(chan struct{}, concurrency)
+	}
+	if err := instance.launchBrowser(); err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// launch starts the browser, must be called with the mutex held
+func (b *browserInstance) launch() error {
+	var (
+		launcherURL    string
+		chromeLauncher *launcher.Launcher
+		connection     *cdp.WebSocket
+		err            error
+	)
+
+	root := rod.New()
+	if b.wsURL != "" {
+		launcherURL = b.wsURL
+		// the connection is opened here so that it can be closed on release
+		connection = &cdp.WebSocket{}
+		if err := connection.Connect(context.Background(), launcherURL, nil); err != nil {
+			return errkit.Wrap(err, fmt.Sprintf("hybrid: failed to connect to chrome instance at %s", launcherURL))
+		}
+		root = root.Client(cdp.New().Start(connection))
+	} else {
+		// create new chrome launcher instance
+		chromeLauncher, err = login.NewChromeLauncher(b.options.Options, b.profile, b.dataStore)
+		if err != nil {
+			return errkit.Wrap(err, "hybrid: could not create chrome launcher")
+		}
+
+		// launch chrome headless process
+		launcherURL, err = chromeLauncher.Launch()
+		if err != nil {
+			return err
+		}
+		root = root.ControlURL(launcherURL)
+	}
+
+	// closeOnError releases the browser if it could not be set up
+	closeOnError := func() {
+		if chromeLauncher != nil {
+			chromeLauncher.Kill()
+		}
+		if connection != nil {
+			_ = connection.Close()
+		}
+	}
+	if browserErr := root.Connect(); browserErr != nil {
+		closeOnError()
+		return errkit.Wrap(browserErr, fmt.Sprintf("hybrid: failed to connect to chrome instance at %s", launcherURL))
+	}
+
+	// create a new browser instance (default to incognito mode)
+	browser := root
+	if !b.options.Options.HeadlessNoIncognito {
+		incognito, err := root.Incognito()
+		if err != nil {
+			closeOnError()
+			return errkit.Wrap(err, "hybrid: failed to create incognito browser")
+		}
+		browser = incognito
+	}
+
+	b.launcher = chromeLauncher
+	b.connection = connection
+	b.root = root
+	b.browser = browser
+	return nil
+}
+
+// acquireSlot waits for a free page slot of the instance
+func (b *browserInstance) acquireSlot() {
+	if b.slots != nil {
+		b.slots <- struct{}{}
+	}
+}
+
+// releaseSlot frees a page slot of the instance
+func (b *browserInstance) releaseSlot() {
+	if b.slots != nil {
+		<-b.slots
+	}
+}
+
+// current returns the current browser along with its generation
+func (b *browserInstance) current() (*rod.Browser, int) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.browser, b.generation
+}
+
+// alive returns true if the browser still answers to the devtools protocol
+func (b *browserInstance) alive(browser *rod.Browser) bool {
+	_, err := proto.BrowserGetVersion{}.Call(browser.Timeout(healthCheckTimeout))
+	return err == nil
+}
+
+// recover restarts the browser of the given generation if it is not alive,
+// restoring the cookies of the last snapshot. It returns an error if the
+// browser could not be restarted or the restart limit has been reached.
+func (b *browserInstance) recover(generation int) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	// the browser has already been restarted by another request
+	if generation != b.generation {
+		return nil
+	}
+	if b.isAlive(b.browser) {
+		return nil
+	}
+	if b.restarts >= b.options.Options.HeadlessMaxRestarts {
+		return errkit.Newf("hybrid: browser is not responding and the restart limit (%d) has been reached", b.options.Options.HeadlessMaxRestarts)
+	}
+	b.restarts++
+
+	gologger.Warning().Msgf("Browser is not responding, restarting it (%d/%d)\n", b.restarts, b.options.Options.HeadlessMaxRestarts)
+	b.release()
+	if err := b.launchBrowser(); err != nil {
+		return errkit.Wrap(err, "hybrid: could not restart browser")
+	}
+	b.generation++
+
+	b.cookiesMutex.Lock()
+	cookies := b.cookies
+	b.cookiesMutex.Unlock()
+	if len(cookies) > 0 {
+		session := &login.Session{Cookies: cookies}
+		if err := b.browser.SetCookies(session.CookieParams()); err != nil {
+			gologger.Warning().Msgf("Could not restore browser cookies: %s\n", err)
+		}
+	}
+	return nil
+}
+
+// snapshotCookies saves the browser cookies so that they are restored after a
+// restart. Snapshots are throttled since they are taken after every navigation.
+func (b *browserInstance) snapshotCookies(browser *rod.Browser) {
+	b.cookiesMutex.Lock()
+	defer b.cookiesMutex.Unlock()
+
+	if time.Since(b.lastSnapshot) < cookieSnapshotInterval {
+		return
+	}
+	cookies, err := browser.GetCookies()
+	if err != nil {
+		return
+	}
+	b.cookies = cookies
+	b.lastSnapshot = time.Now()
+}
+
+// release closes the browser, must be called with the mutex held. Browsers
+// launched locally are killed and their temporary data dir is removed, the
+// connection to remote browsers is closed without closing them.
+func (b *browserInstance) release() {
+	if b.browser != nil && b.browser != b.root {
+		_ = b.browser.Timeout(healthCheckTimeout).Close()
+	}
+	if b.launcher != nil {
+		_ = b.root.Timeout(healthCheckTimeout).Close()
+		b.launcher.Kill()
+		// the data dir provided by the user is kept
+		if b.options.Options.ChromeDataDir == "" {
+			b.launcher.Cleanup()
+		}
+		b.launcher = nil
+	}
+	if b.connection != nil {
+		_ = b.connection.Close()
+		b.connection = nil
+	}
+}
+
+// close releases the browser of the instance
+func (b *browserInstance) close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.release()
+}
+
+// navigateRequestWithRecovery navigates the request on the instance browser.
+// If the browser crashed or disconnected it is restarted and the request is
+// pushed back to the queue, each request is only re-queued once.
+func (c *Crawler) navigateRequestWithRecovery(instance *browserInstance) common.DoRequestFunc {
+	return c.withRecovery(instance, c.navigateRequest)
+}
+
+// navigateFunc navigates a request on a browser
+type navigateFunc func(s *common.CrawlSession, browser *rod.Browser, request *navigation.Request) (*navigation.Response, error)
+
+// withRecovery returns a request function navigating the requests on the
+// instance browser with navigate, restarting it on failures.
+func (c *Crawler) withRecovery(instance *browserInstance, navigate navigateFunc) common.DoRequestFunc {
+	return func(s *common.CrawlSession, request *navigation.Request) (*navigation.Response, error) {
+		instance.acquireSlot()
+		defer instance.releaseSlot()
+
+		browser, generation := instance.current()
+		response, err := navigate(s, browser, request)
+		if c.Options.Options.HeadlessMaxRestarts <= 0 {
+			return response, err
+		}
+		if err == nil {
+			instance.snapshotCookies(browser)
+			return response, nil
+		}
+		if instance.isAlive(browser) {
+			return response, err
+		}
+		if recoverErr := instance.recover(generation); recoverErr != nil {
+			gologger.Error().Msgf("%s\n", recoverErr)
+			return response, err
+		}
+		if _, requeued := c.requeued.LoadOrStore(request, struct{}{}); requeued {
+			return response, err
+		}
+		s.Queue.Push(request, request.Depth)
+		return nil, common.ErrRequeued
+	}
+}
@@ -0,0 +1,70 @@
+package hybrid
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/projectdiscovery/katana/pkg/engine/common"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/types"
+	"github.com/projectdiscovery/katana/pkg/utils/queue"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRecovery(t *testing.T) {
+	options := &types.CrawlerOptions{Options: &types.Options{HeadlessMaxRestarts: 1}}
+	crawler := &Crawler{Shared: &common.Shared{Options: options}}
+
+	alive, launches := true, 0
+	instance := &browserInstance{options: options, lastSnapshot: time.Now()}
+	instance.launchBrowser = func() error {
+		launches++
+		alive = true
+		return nil
+	}
+	instance.isAlive = func(browser *rod.Browser) bool { return alive }
+
+	// the browser crashes on every navigation of /crash
+	doRequest := crawler.withRecovery(instance, func(s *common.CrawlSession, browser *rod.Browser, request *navigation.Request) (*navigation.Response, error) {
+		switch request.URL {
+		case "https://example.com/crash":
+			alive = false
+			return nil, errors.New("browser crashed")
+		case "https://example.com/timeout":
+			return nil, errors.New("navigation timeout")
+		}
+		return &navigation.Response{}, nil
+	})
+
+	crawlQueue, err := queue.New("depth-first", 1)
+	require.Nil(t, err, "could not create queue")
+	session := &common.CrawlSession{Queue: crawlQueue}
+
+	_, err = doRequest(session, &navigation.Request{URL: "https://example.com/"})
+	require.Nil(t, err)
+	require.Equal(t, 0, launches)
+
+	// the browser is restarted and the request is re-queued once
+	crash := &navigation.Request{URL: "https://example.com/crash", Depth: 1}
+	_, err = doRequest(session, crash)
+	require.ErrorIs(t, err, common.ErrRequeued)
+	require.Equal(t, 1, launches)
+	require.Equal(t, 1, instance.generation)
+	require.Equal(t, 1, crawlQueue.Len())
+	require.Equal(t, crash, <-crawlQueue.Pop())
+
+	// the restart limit is reached, the error is returned
+	_, err = doRequest(session, crash)
+	require.EqualError(t, err, "browser crashed")
+	require.Equal(t, 1, launches)
+	require.Equal(t, 0, crawlQueue.Len())
+
+	// failures of an alive browser are returned without restarting it
+	alive = true
+	_, err = doRequest(session, &navigation.Request{URL: "https://example.com/timeout"})
+	require.EqualError(t, err, "navigation timeout")
+	require.Equal(t, 1, launches)
+	require.Equal(t, 0, crawlQueue.Len())
+}
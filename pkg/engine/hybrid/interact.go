@@ -0,0 +1,194 @@
+package hybrid
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/katana/pkg/types"
+	"github.com/projectdiscovery/utils/errkit"
+)
+
+// defaultConsentSelectors are the accept buttons of common consent managers
+var defaultConsentSelectors = []string{
+	"#onetrust-accept-btn-handler",                                        // OneTrust
+	"#CybotCookiebotDialogBodyLevelButtonLevelOptinAllowAll",              // Cookiebot
+	"#CybotCookiebotDialogBodyButtonAccept",                               // Cookiebot
+	"#didomi-notice-agree-button",                                         // Didomi
+	".qc-cmp2-summary-buttons button[mode=primary]",                       // Quantcast
+	"#truste-consent-button",                                              // TrustArc
+	".osano-cm-accept-all",                                                // Osano
+	".cky-btn-accept",                                                     // CookieYes
+	".cmplz-accept",                                                       // Complianz
+	"[data-tid=banner-accept]",                                            // Termly
+	".cm-btn-accept-all",                                                  // Klaro
+	"#BorlabsCookieBox a[data-cookie-accept-all]",                         // Borlabs
+	".iubenda-cs-accept-btn",                                              // iubenda
+	"#axeptio_btn_acceptAll",                                              // Axeptio
+	"#cookie_action_close_header",                                         // GDPR Cookie Consent
+	".cc-allow, .cc-btn.cc-dismiss",                                       // cookieconsent
+	"#accept-cookies, #acceptCookies, #cookie-accept, .js-accept-cookies", // generic
+}
+
+// clickConsentJS clicks the first visible element matching the selectors
+// and returns its selector, or an empty string if none is found.
+const clickConsentJS = `(selectors) => {
+	const visible = el => {
+		const rect = el.getBoundingClientRect();
+		const style = getComputedStyle(el);
+		return rect.width > 0 && rect.height > 0 && style.visibility !== "hidden" && style.display !== "none";
+	};
+	for (const selector of selectors) {
+		let elements = [];
+		try { elements = document.querySelectorAll(selector); } catch (e) { continue; }
+		for (const el of elements) {
+			if (visible(el)) { el.click(); return selector; }
+		}
+	}
+	return "";
+}`
+
+// pageSizeJS returns the page height and number of elements
+// which are used to detect newly loaded content.
+const pageSizeJS = `() => [(document.scrollingElement || document.documentElement).scrollHeight, document.getElementsByTagName("*").length]`
+
+// scrollJS scrolls to the bottom of the page
+const scrollJS = `() => window.scrollTo(0, (document.scrollingElement || document.documentElement).scrollHeight)`
+
+// dialogHandler answers the javascript dialogs opened by the pages
+type dialogHandler struct {
+	accept     bool
+	promptText string
+}
+
+// newDialogHandler parses the dialog response, either accept, dismiss
+// or accept:<text> to answer prompts with the text.
+func newDialogHandler(value string) (*dialogHandler, error) {
+	action, text, _ := strings.Cut(value, ":")
+	switch strings.ToLower(strings.TrimSpace(action)) {
+	case "", "accept":
+		return &dialogHandler{accept: true, promptText: text}, nil
+	case "dismiss":
+		return &dialogHandler{}, nil
+	}
+	return nil, errkit.Newf("hybrid: invalid dialog response %q, expected accept, dismiss or accept:<text>", value)
+}
+
+// loadConsentSelectors returns the consent selectors of the options,
+// or the default ones if no custom selector was specified.
+func loadConsentSelectors(options *types.Options) ([]string, error) {
+	if !options.HeadlessConsent {
+		return nil, nil
+	}
+	if options.HeadlessConsentSelectors == "" {
+		return defaultConsentSelectors, nil
+	}
+	file, err := os.Open(options.HeadlessConsentSelectors)
+	if err != nil {
+		return nil, errkit.Wrap(err, "hybrid: could not read consent selectors")
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var selectors []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		selectors = append(selectors, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errkit.Wrap(err, "hybrid: could not read consent selectors")
+	}
+	return selectors, nil
+}
+
+// handleDialogs answers the javascript dialogs of the page so that they
+// don't block it until timeout. The returned function stops the handling.
+func (c *Crawler) handleDialogs(page *rod.Page) func() {
+	eventsPage, cancel := page.WithCancel()
+	wait := eventsPage.EachEvent(func(e *proto.PageJavascriptDialogOpening) {
+		answer := proto.PageHandleJavaScriptDialog{Accept: c.dialogs.accept}
+		if e.Type == proto.PageDialogTypePrompt {
+			answer.PromptText = c.dialogs.promptText
+			if answer.PromptText == "" {
+				answer.PromptText = e.DefaultPrompt
+			}
+		}
+		if err := answer.Call(page); err != nil {
+			gologger.Debug().Msgf("could not handle %s dialog: %s\n", e.Type, err)
+		}
+	})
+	go wait()
+	return cancel
+}
+
+// clickConsent clicks the accept button of the consent manager of the page
+func (c *Crawler) clickConsent(page *rod.Page) {
+	if len(c.consentSelectors) == 0 {
+		return
+	}
+	result, err := page.Eval(clickConsentJS, c.consentSelectors)
+	if err != nil {
+		gologger.Debug().Msgf("could not click consent: %s\n", err)
+		return
+	}
+	if selector := result.Value.Str(); selector != "" {
+		gologger.Debug().Msgf("clicked consent %s\n", selector)
+		c.waitDOMQuiet(page)
+	}
+}
+
+// scrollPage scrolls the page up to the configured number of times until no
+// new content is loaded, each loaded batch of content is passed to parse.
+func (c *Crawler) scrollPage(page *rod.Page, parse func(body string)) {
+	for i := 0; i < c.Options.Options.HeadlessScroll; i++ {
+		before, err := pageSize(page)
+		if err != nil {
+			return
+		}
+		waitIdle := page.WaitRequestIdle(requestIdleDuration, nil, nil, nil)
+		if _, err := page.Eval(scrollJS); err != nil {
+			gologger.Debug().Msgf("could not scroll page: %s\n", err)
+			return
+		}
+		waitIdle()
+		c.waitDOMQuiet(page)
+		after, err := pageSize(page)
+		if err != nil || after == before {
+			return
+		}
+
+		body, err := page.HTML()
+		if err != nil {
+			return
+		}
+		parse(body)
+	}
+}
+
+// pageSize returns the height and number of elements of the page
+func pageSize(page *rod.Page) ([2]int, error) {
+	result, err := page.Eval(pageSizeJS)
+	if err != nil {
+		return [2]int{}, err
+	}
+	values := result.Value.Arr()
+	if len(values) != 2 {
+		return [2]int{}, errkit.New("hybrid: invalid page size")
+	}
+	return [2]int{values[0].Int(), values[1].Int()}, nil
+}
+
+// waitDOMQuiet waits for the dom of the page to stop changing
+func (c *Crawler) waitDOMQuiet(page *rod.Page) {
+	if _, err := page.Eval(domQuietJS, defaultDOMQuietDuration.Milliseconds()); err != nil {
+		gologger.Debug().Msgf("could not wait for dom: %s\n", err)
+	}
+}
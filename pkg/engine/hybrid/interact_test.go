@@ -0,0 +1,42 @@
+package hybrid
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/projectdiscovery/katana/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDialogHandler(t *testing.T) {
+	handler, err := newDialogHandler("accept:katana:test")
+	require.Nil(t, err, "could not parse dialog response")
+	require.True(t, handler.accept)
+	require.Equal(t, "katana:test", handler.promptText)
+
+	handler, err = newDialogHandler("Dismiss")
+	require.Nil(t, err, "could not parse dialog response")
+	require.False(t, handler.accept)
+
+	_, err = newDialogHandler("ignore")
+	require.NotNil(t, err, "invalid dialog response was parsed")
+}
+
+func TestLoadConsentSelectors(t *testing.T) {
+	selectors, err := loadConsentSelectors(&types.Options{})
+	require.Nil(t, err, "could not load consent selectors")
+	require.Empty(t, selectors, "consent selectors loaded with consent disabled")
+
+	selectors, err = loadConsentSelectors(&types.Options{HeadlessConsent: true})
+	require.Nil(t, err, "could not load consent selectors")
+	require.Equal(t, defaultConsentSelectors, selectors)
+
+	file := filepath.Join(t.TempDir(), "selectors.txt")
+	err = os.WriteFile(file, []byte("#accept\n\n  .consent button  \n"), 0644)
+	require.Nil(t, err, "could not write selectors")
+
+	selectors, err = loadConsentSelectors(&types.Options{HeadlessConsent: true, HeadlessConsentSelectors: file})
+	require.Nil(t, err, "could not load consent selectors")
+	require.Equal(t, []string{"#accept", ".consent button"}, selectors)
+}
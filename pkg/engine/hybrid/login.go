@@ -0,0 +1,45 @@
+package hybrid
+
+import (
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/projectdiscovery/katana/pkg/engine/login"
+	"github.com/projectdiscovery/utils/errkit"
+)
+
+// executeLogin runs the login flow in the primary browser of the pool so that
+// the crawl pages share the authenticated cookies, which are then copied
+// to the other browsers of the pool.
+func (c *Crawler) executeLogin(flow *login.Flow) (*login.Session, error) {
+	timeout := time.Duration(c.Options.Options.Timeout) * time.Second
+	primary := c.pool.primary()
+	browser, _ := primary.current()
+	session, err := flow.Execute(browser, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.pool.setCookies(primary, session.CookieParams()); err != nil {
+		return nil, errkit.Wrap(err, "hybrid: could not set session cookies")
+	}
+	return session, nil
+}
+
+// addSessionStorageToPage restores the web storage of the login session on the page
+func (c *Crawler) addSessionStorageToPage(page *rod.Page) error {
+	if c.Authenticator == nil {
+		return nil
+	}
+	session := c.Authenticator.Session()
+	if session == nil {
+		return nil
+	}
+	script := session.StorageScript()
+	if script == "" {
+		return nil
+	}
+	if _, err := page.EvalOnNewDocument(script); err != nil {
+		return errkit.Wrap(err, "hybrid: could not restore session storage")
+	}
+	return nil
+}
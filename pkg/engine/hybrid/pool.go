@@ -0,0 +1,99 @@
+package hybrid
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/katana/pkg/engine/profile"
+	"github.com/projectdiscovery/katana/pkg/types"
+)
+
+// browserPool is a pool of browser instances to which crawl
+// sessions are assigned based on the number of active sessions.
+type browserPool struct {
+	mutex     sync.Mutex
+	instances []*browserInstance
+	// sessions is the number of active crawl sessions of each instance
+	sessions map[*browserInstance]int
+}
+
+// newBrowserPool connects to the chrome websocket urls of the options or launches
+// the configured number of local chrome instances, each one with its own data dir.
+func newBrowserPool(options *types.CrawlerOptions, browserProfile *profile.Profile, dataStore string) (*browserPool, error) {
+	pool := &browserPool{sessions: make(map[*browserInstance]int)}
+
+	wsURLs := options.Options.ParseChromeWSUrls()
+	count := len(wsURLs)
+	if count == 0 {
+		count = max(options.Options.HeadlessInstances, 1)
+	}
+
+	for i := 0; i < count; i++ {
+		var wsURL string
+		if len(wsURLs) > 0 {
+			wsURL = wsURLs[i]
+		}
+		instanceDataStore := dataStore
+		if count > 1 {
+			instanceDataStore = filepath.Join(dataStore, fmt.Sprintf("instance-%d", i))
+		}
+		instance, err := newBrowserInstance(options, browserProfile, instanceDataStore, wsURL)
+		if err != nil {
+			pool.close()
+			return nil, err
+		}
+		pool.instances = append(pool.instances, instance)
+		pool.sessions[instance] = 0
+	}
+	return pool, nil
+}
+
+// acquire returns the instance with the least active sessions
+func (p *browserPool) acquire() *browserInstance {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	selected := p.instances[0]
+	for _, instance := range p.instances[1:] {
+		if p.sessions[instance] < p.sessions[selected] {
+			selected = instance
+		}
+	}
+	p.sessions[selected]++
+	return selected
+}
+
+// release marks a session of the instance as finished
+func (p *browserPool) release(instance *browserInstance) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.sessions[instance]--
+}
+
+// primary returns the first instance of the pool
+func (p *browserPool) primary() *browserInstance {
+	return p.instances[0]
+}
+
+// setCookies sets the cookies on all the instances of the pool except the given one
+func (p *browserPool) setCookies(except *browserInstance, cookies []*proto.NetworkCookieParam) error {
+	for _, instance := range p.instances {
+		if instance == except {
+			continue
+		}
+		browser, _ := instance.current()
+		if err := browser.SetCookies(cookies); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close releases the browsers of the pool
+func (p *browserPool) close() {
+	for _, instance := range p.instances {
+		instance.close()
+	}
+}
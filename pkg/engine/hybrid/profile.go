@@ -0,0 +1,84 @@
+package hybrid
+
+import (
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/utils/errkit"
+)
+
+// maxTouchPoints is the number of touch points of touch enabled profiles
+const maxTouchPoints = 5
+
+// applyProfileToPage emulates the device, locale and user agent of the
+// profile on the page and adds the stealth patches if enabled.
+func (c *Crawler) applyProfileToPage(page *rod.Page) error {
+	p := c.profile
+	if p == nil {
+		return nil
+	}
+
+	if p.Width > 0 {
+		scaleFactor := p.DeviceScaleFactor
+		if scaleFactor == 0 {
+			scaleFactor = 1
+		}
+		err := proto.EmulationSetDeviceMetricsOverride{
+			Width:             p.Width,
+			Height:            p.Height,
+			DeviceScaleFactor: scaleFactor,
+			Mobile:            p.Mobile,
+		}.Call(page)
+		if err != nil {
+			return errkit.Wrap(err, "hybrid: could not set device metrics")
+		}
+	}
+	if p.Touch {
+		touchPoints := maxTouchPoints
+		if err := (proto.EmulationSetTouchEmulationEnabled{Enabled: true, MaxTouchPoints: &touchPoints}).Call(page); err != nil {
+			return errkit.Wrap(err, "hybrid: could not enable touch emulation")
+		}
+	}
+	if p.UserAgent != "" {
+		if err := page.SetUserAgent(p.UserAgentOverride()); err != nil {
+			return errkit.Wrap(err, "hybrid: could not set user agent")
+		}
+	}
+	if p.Locale != "" {
+		if err := (proto.EmulationSetLocaleOverride{Locale: p.Locale}).Call(page); err != nil {
+			return errkit.Wrap(err, "hybrid: could not set locale")
+		}
+	}
+	if p.Timezone != "" {
+		if err := (proto.EmulationSetTimezoneOverride{TimezoneID: p.Timezone}).Call(page); err != nil {
+			return errkit.Wrap(err, "hybrid: could not set timezone")
+		}
+	}
+	if p.Geolocation != nil {
+		browser := page.Browser()
+		err := proto.BrowserGrantPermissions{
+			Permissions:      []proto.BrowserPermissionType{proto.BrowserPermissionTypeGeolocation},
+			BrowserContextID: browser.BrowserContextID,
+		}.Call(browser)
+		if err != nil {
+			return errkit.Wrap(err, "hybrid: could not grant geolocation permission")
+		}
+		accuracy := p.Geolocation.Accuracy
+		if accuracy == 0 {
+			accuracy = 100
+		}
+		err = proto.EmulationSetGeolocationOverride{
+			Latitude:  &p.Geolocation.Latitude,
+			Longitude: &p.Geolocation.Longitude,
+			Accuracy:  &accuracy,
+		}.Call(page)
+		if err != nil {
+			return errkit.Wrap(err, "hybrid: could not set geolocation")
+		}
+	}
+	if script := p.StealthScript(); script != "" {
+		if _, err := page.EvalOnNewDocument(script); err != nil {
+			return errkit.Wrap(err, "hybrid: could not add stealth script")
+		}
+	}
+	return nil
+}
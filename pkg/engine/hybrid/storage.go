@@ -0,0 +1,178 @@
+package hybrid
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/katana/pkg/engine/common"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+	"github.com/projectdiscovery/utils/errkit"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+// storageTag is the tag of the requests discovered from the web storage
+const storageTag = "storage"
+
+// webStorageJS returns the origin, web storage and indexeddb database names of the current document
+const webStorageJS = `async () => {
+	const storage = {origin: location.origin, local: {}, session: {}, indexeddb: []};
+	try { Object.assign(storage.local, window.localStorage); } catch (e) {}
+	try { Object.assign(storage.session, window.sessionStorage); } catch (e) {}
+	try {
+		if (window.indexedDB && indexedDB.databases) {
+			storage.indexeddb = (await indexedDB.databases()).map(db => db.name).filter(Boolean);
+		}
+	} catch (e) {}
+	return JSON.stringify(storage);
+}`
+
+// serverCookies keeps track of the cookies set by servers through the
+// Set-Cookie header so that the ones set by javascript can be told apart.
+// Cookies are keyed by their domain and name as the same name may be used
+// by the server on a host and by javascript on another one.
+type serverCookies struct {
+	sync.RWMutex
+	cookies map[string]struct{}
+}
+
+func newServerCookies() *serverCookies {
+	return &serverCookies{cookies: make(map[string]struct{})}
+}
+
+// addFromHeaders records the cookies of the Set-Cookie response headers of the url
+func (sc *serverCookies) addFromHeaders(responseURL string, headers []*proto.FetchHeaderEntry) {
+	parsed, err := url.Parse(responseURL)
+	if err != nil {
+		return
+	}
+	sc.Lock()
+	defer sc.Unlock()
+	for _, header := range headers {
+		if !strings.EqualFold(header.Name, "Set-Cookie") {
+			continue
+		}
+		// multiple Set-Cookie headers are joined by new lines
+		for _, value := range strings.Split(header.Value, "\n") {
+			cookie, err := http.ParseSetCookie(strings.TrimSpace(value))
+			if err != nil {
+				continue
+			}
+			// cookies without domain attribute are host-only cookies
+			domain := cookie.Domain
+			if domain == "" {
+				domain = parsed.Hostname()
+			}
+			sc.cookies[serverCookieKey(domain, cookie.Name)] = struct{}{}
+		}
+	}
+}
+
+// has returns true if a cookie with the name was set by a server for the domain
+func (sc *serverCookies) has(domain, name string) bool {
+	sc.RLock()
+	defer sc.RUnlock()
+	_, ok := sc.cookies[serverCookieKey(domain, name)]
+	return ok
+}
+
+func serverCookieKey(domain, name string) string {
+	return strings.ToLower(strings.TrimPrefix(domain, ".")) + ";" + name
+}
+
+// extractStorage returns the web storage of the page origin along with
+// the cookies of the origin which were set by javascript.
+func (c *Crawler) extractStorage(page *rod.Page) (*navigation.WebStorage, error) {
+	result, err := page.Eval(webStorageJS)
+	if err != nil {
+		return nil, errkit.Wrap(err, "hybrid: could not get web storage")
+	}
+	var storage struct {
+		Origin    string            `json:"origin"`
+		Local     map[string]string `json:"local"`
+		Session   map[string]string `json:"session"`
+		IndexedDB []string          `json:"indexeddb"`
+	}
+	if err := json.Unmarshal([]byte(result.Value.Str()), &storage); err != nil {
+		return nil, errkit.Wrap(err, "hybrid: could not decode web storage")
+	}
+	// opaque origins (about:blank, data urls, etc) have no storage
+	if storage.Origin == "" || storage.Origin == "null" {
+		return nil, nil
+	}
+	webStorage := &navigation.WebStorage{
+		Origin:         storage.Origin,
+		LocalStorage:   storage.Local,
+		SessionStorage: storage.Session,
+		IndexedDB:      storage.IndexedDB,
+	}
+
+	origin, err := urlutil.Parse(storage.Origin)
+	if err != nil {
+		return nil, errkit.Wrap(err, "hybrid: could not parse origin")
+	}
+	cookies, err := proto.NetworkGetAllCookies{}.Call(page)
+	if err != nil {
+		return nil, errkit.Wrap(err, "hybrid: could not get cookies")
+	}
+	for _, cookie := range cookies.Cookies {
+		if cookie.HTTPOnly || c.serverCookies.has(cookie.Domain, cookie.Name) || !cookieMatchesHost(cookie.Domain, origin.Hostname()) {
+			continue
+		}
+		webStorage.Cookies = append(webStorage.Cookies, navigation.Cookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			SameSite: string(cookie.SameSite),
+		})
+	}
+
+	if len(webStorage.LocalStorage) == 0 && len(webStorage.SessionStorage) == 0 && len(webStorage.IndexedDB) == 0 && len(webStorage.Cookies) == 0 {
+		return nil, nil
+	}
+	return webStorage, nil
+}
+
+// enqueueStorageURLs queues the url-like values found in the web storage
+func (c *Crawler) enqueueStorageURLs(s *common.CrawlSession, request *navigation.Request, response *navigation.Response) {
+	storage := response.Storage
+	if storage == nil {
+		return
+	}
+
+	var navigationRequests []*navigation.Request
+	add := func(attribute string, values map[string]string) {
+		for _, value := range values {
+			for _, item := range utils.ExtractURLLikeValues(value) {
+				navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(item, request.URL, storageTag, attribute, response))
+			}
+		}
+	}
+	add("localstorage", storage.LocalStorage)
+	add("sessionstorage", storage.SessionStorage)
+	cookies := make(map[string]string, len(storage.Cookies))
+	for _, cookie := range storage.Cookies {
+		value := cookie.Value
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			value = unescaped
+		}
+		cookies[cookie.Name] = value
+	}
+	add("cookie", cookies)
+
+	c.Enqueue(s.Queue, navigationRequests...)
+}
+
+// cookieMatchesHost returns true if a cookie of the domain is sent to the host
+func cookieMatchesHost(domain, host string) bool {
+	domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+	host = strings.ToLower(host)
+	return domain == host || strings.HasSuffix(host, "."+domain)
+}
@@ -0,0 +1,24 @@
+package hybrid
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerCookies(t *testing.T) {
+	cookies := newServerCookies()
+	cookies.addFromHeaders("https://app.example.com/login", []*proto.FetchHeaderEntry{
+		{Name: "Content-Type", Value: "text/html"},
+		{Name: "set-cookie", Value: "session=abc; Path=/; HttpOnly\ntheme=dark; Domain=.example.com"},
+	})
+
+	require.True(t, cookies.has("app.example.com", "session"))
+	require.True(t, cookies.has(".example.com", "theme"))
+	require.True(t, cookies.has("example.com", "theme"))
+	// same name set on another host, e.g. by javascript
+	require.False(t, cookies.has("api.example.com", "session"))
+	require.False(t, cookies.has("app.example.com", "theme"))
+	require.False(t, cookies.has("app.example.com", "tracking"))
+}
@@ -0,0 +1,323 @@
+package hybrid
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/katana/pkg/types"
+	"github.com/projectdiscovery/utils/errkit"
+)
+
+// waitKind is a page readiness condition
+type waitKind string
+
+const (
+	// waitStable waits for the page to be stable (dom, network and rendering)
+	waitStable waitKind = "stable"
+	// waitNetworkIdle waits for the in-flight requests to be below a threshold
+	waitNetworkIdle waitKind = "network-idle"
+	// waitSelector waits for an element matching a css selector
+	waitSelector waitKind = "selector"
+	// waitJS waits for a javascript expression to be truthy
+	waitJS waitKind = "js"
+	// waitDOMQuiet waits for the dom to stop changing
+	waitDOMQuiet waitKind = "dom-quiet"
+	// waitTimeout bounds the total wait, or waits for the duration if alone
+	waitTimeout waitKind = "timeout"
+)
+
+const (
+	// networkIdleDuration is the duration the in-flight requests must stay below the threshold
+	networkIdleDuration = 500 * time.Millisecond
+	// defaultDOMQuietDuration is the default duration without dom mutations
+	defaultDOMQuietDuration = 500 * time.Millisecond
+	// waitPollInterval is the polling interval of the network idle condition
+	waitPollInterval = 100 * time.Millisecond
+)
+
+// domQuietJS resolves once no dom mutation happened for the duration in milliseconds
+const domQuietJS = `(quiet) => new Promise(resolve => {
+	let timer;
+	const observer = new MutationObserver(() => { clearTimeout(timer); timer = setTimeout(done, quiet); });
+	function done() { observer.disconnect(); resolve(true); }
+	timer = setTimeout(done, quiet);
+	observer.observe(document, {subtree: true, childList: true, attributes: true, characterData: true});
+})`
+
+// waitCondition is a single condition of a wait strategy
+type waitCondition struct {
+	kind      waitKind
+	value     string
+	threshold int
+	duration  time.Duration
+}
+
+// waitStrategy is a list of conditions waited in order, bounded by the timeout
+type waitStrategy struct {
+	conditions []waitCondition
+	timeout    time.Duration
+}
+
+// waitRule applies a wait strategy to the urls matching the pattern
+type waitRule struct {
+	pattern  *regexp.Regexp
+	strategy *waitStrategy
+}
+
+// waitStrategies selects the wait strategy of a page by its url
+type waitStrategies struct {
+	global *waitStrategy
+	rules  []waitRule
+}
+
+// newWaitStrategies parses the global wait strategy and the per url rules.
+// The global strategy defaults to waiting for the page to be stable.
+func newWaitStrategies(options *types.Options) (*waitStrategies, error) {
+	strategies := &waitStrategies{
+		global: &waitStrategy{conditions: []waitCondition{{kind: waitStable, duration: time.Duration(options.TimeStable) * time.Second}}},
+	}
+	if options.HeadlessWait != "" {
+		strategy, err := parseWaitStrategy(options.HeadlessWait, options.TimeStable)
+		if err != nil {
+			return nil, err
+		}
+		strategies.global = strategy
+	}
+	for _, value := range options.HeadlessWaitRules {
+		pattern, strategyValue, found := strings.Cut(value, "=>")
+		if !found {
+			return nil, errkit.Newf("hybrid: invalid wait rule %q, expected <url-regex> => <strategy>", value)
+		}
+		regex, err := regexp.Compile(strings.TrimSpace(pattern))
+		if err != nil {
+			return nil, errkit.Wrap(err, "hybrid: invalid wait rule regex")
+		}
+		strategy, err := parseWaitStrategy(strategyValue, options.TimeStable)
+		if err != nil {
+			return nil, err
+		}
+		strategies.rules = append(strategies.rules, waitRule{pattern: regex, strategy: strategy})
+	}
+
+	// stable waits longer than the page timeout would always time out
+	timeout := time.Duration(options.Timeout) * time.Second
+	strategiesList := []*waitStrategy{strategies.global}
+	for _, rule := range strategies.rules {
+		strategiesList = append(strategiesList, rule.strategy)
+	}
+	for _, strategy := range strategiesList {
+		for i, condition := range strategy.conditions {
+			if condition.kind == waitStable && timeout > 0 && timeout < condition.duration {
+				gologger.Warning().Msgf("timeout is less than time stable, setting time stable to half of timeout to avoid timeout\n")
+				strategy.conditions[i].duration = timeout / 2
+				gologger.Warning().Msgf("setting time stable to %s\n", strategy.conditions[i].duration)
+			}
+		}
+	}
+	return strategies, nil
+}
+
+// forURL returns the strategy of the first rule matching the url or the global one
+func (w *waitStrategies) forURL(URL string) *waitStrategy {
+	for _, rule := range w.rules {
+		if rule.pattern.MatchString(URL) {
+			return rule.strategy
+		}
+	}
+	return w.global
+}
+
+// parseWaitStrategy parses a strategy made of ";" separated conditions, e.g.
+//
+//	network-idle:2;selector:#content table;timeout:15s
+func parseWaitStrategy(value string, timeStable int) (*waitStrategy, error) {
+	strategy := &waitStrategy{}
+	for _, item := range strings.Split(value, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, argument, _ := strings.Cut(item, ":")
+		condition := waitCondition{kind: waitKind(strings.ToLower(strings.TrimSpace(name)))}
+		argument = strings.TrimSpace(argument)
+
+		var err error
+		switch condition.kind {
+		case waitStable:
+			condition.duration = time.Duration(timeStable) * time.Second
+			if argument != "" {
+				condition.duration, err = time.ParseDuration(argument)
+			}
+		case waitNetworkIdle:
+			if argument != "" {
+				condition.threshold, err = strconv.Atoi(argument)
+				if err == nil && condition.threshold < 0 {
+					err = errkit.New("threshold can't be negative")
+				}
+			}
+		case waitSelector, waitJS:
+			if argument == "" {
+				err = errkit.New("missing value")
+			}
+			condition.value = argument
+		case waitDOMQuiet:
+			condition.duration = defaultDOMQuietDuration
+			if argument != "" {
+				condition.duration, err = time.ParseDuration(argument)
+			}
+		case waitTimeout:
+			strategy.timeout, err = time.ParseDuration(argument)
+			if err == nil && strategy.timeout <= 0 {
+				err = errkit.New("timeout must be positive")
+			}
+			if err != nil {
+				return nil, errkit.Wrap(err, "hybrid: invalid wait condition "+item)
+			}
+			continue
+		default:
+			return nil, errkit.Newf("hybrid: unknown wait condition %q", name)
+		}
+		if err != nil {
+			return nil, errkit.Wrap(err, "hybrid: invalid wait condition "+item)
+		}
+		strategy.conditions = append(strategy.conditions, condition)
+	}
+	if len(strategy.conditions) == 0 && strategy.timeout == 0 {
+		return nil, errkit.Newf("hybrid: empty wait strategy %q", value)
+	}
+	return strategy, nil
+}
+
+// hasCondition returns true if the strategy contains a condition of the kind
+func (s *waitStrategy) hasCondition(kind waitKind) bool {
+	for _, condition := range s.conditions {
+		if condition.kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// pageWaiter waits for a page to be ready according to a strategy. It is
+// created before the navigation so that in-flight requests can be tracked.
+type pageWaiter struct {
+	strategy *waitStrategy
+	page     *rod.Page
+	cancel   func()
+
+	mutex    sync.Mutex
+	inflight map[proto.NetworkRequestID]struct{}
+}
+
+// start starts tracking the page for the strategy, stop must be called once done
+func (s *waitStrategy) start(page *rod.Page) *pageWaiter {
+	w := &pageWaiter{strategy: s, page: page, cancel: func() {}}
+	if !s.hasCondition(waitNetworkIdle) {
+		return w
+	}
+
+	w.inflight = make(map[proto.NetworkRequestID]struct{})
+	eventsPage, cancel := page.WithCancel()
+	w.cancel = cancel
+	wait := eventsPage.EachEvent(
+		func(e *proto.NetworkRequestWillBeSent) {
+			w.mutex.Lock()
+			w.inflight[e.RequestID] = struct{}{}
+			w.mutex.Unlock()
+		},
+		func(e *proto.NetworkLoadingFinished) {
+			w.mutex.Lock()
+			delete(w.inflight, e.RequestID)
+			w.mutex.Unlock()
+		},
+		func(e *proto.NetworkLoadingFailed) {
+			w.mutex.Lock()
+			delete(w.inflight, e.RequestID)
+			w.mutex.Unlock()
+		},
+	)
+	go wait()
+	return w
+}
+
+// stop stops tracking the page
+func (w *pageWaiter) stop() {
+	w.cancel()
+}
+
+// wait waits for the conditions of the strategy in order. The page timeout
+// bounds the wait unless the strategy specifies a shorter one.
+func (w *pageWaiter) wait() error {
+	page := w.page
+	if w.strategy.timeout > 0 {
+		ctx, cancel := context.WithTimeout(page.GetContext(), w.strategy.timeout)
+		defer cancel()
+		page = page.Context(ctx)
+
+		// a strategy made only of a timeout waits for the duration
+		if len(w.strategy.conditions) == 0 {
+			<-ctx.Done()
+			return nil
+		}
+	}
+
+	for _, condition := range w.strategy.conditions {
+		if err := w.waitCondition(page, condition); err != nil {
+			return errkit.Wrap(err, "could not wait for "+string(condition.kind))
+		}
+	}
+	return nil
+}
+
+func (w *pageWaiter) waitCondition(page *rod.Page, condition waitCondition) error {
+	switch condition.kind {
+	case waitStable:
+		return page.WaitStable(condition.duration)
+	case waitNetworkIdle:
+		return w.waitNetworkIdle(page.GetContext(), condition.threshold)
+	case waitSelector:
+		_, err := page.Element(condition.value)
+		return err
+	case waitJS:
+		return page.Wait(rod.Eval(`() => Boolean(` + condition.value + `)`))
+	case waitDOMQuiet:
+		_, err := page.Eval(domQuietJS, condition.duration.Milliseconds())
+		return err
+	}
+	return nil
+}
+
+// waitNetworkIdle waits for the in-flight requests to stay at or below
+// the threshold for the network idle duration.
+func (w *pageWaiter) waitNetworkIdle(ctx context.Context, threshold int) error {
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	var idleSince time.Time
+	for {
+		w.mutex.Lock()
+		inflight := len(w.inflight)
+		w.mutex.Unlock()
+
+		if inflight > threshold {
+			idleSince = time.Time{}
+		} else if idleSince.IsZero() {
+			idleSince = time.Now()
+		} else if time.Since(idleSince) >= networkIdleDuration {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
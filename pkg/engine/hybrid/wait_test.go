@@ -0,0 +1,56 @@
+package hybrid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/goflags"
+	"github.com/projectdiscovery/katana/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWaitStrategy(t *testing.T) {
+	strategy, err := parseWaitStrategy("network-idle:2; selector:#content table ;dom-quiet;timeout:15s", 1)
+	require.Nil(t, err, "could not parse wait strategy")
+	require.Equal(t, 15*time.Second, strategy.timeout)
+	require.Equal(t, []waitCondition{
+		{kind: waitNetworkIdle, threshold: 2},
+		{kind: waitSelector, value: "#content table"},
+		{kind: waitDOMQuiet, duration: defaultDOMQuietDuration},
+	}, strategy.conditions)
+
+	strategy, err = parseWaitStrategy("stable", 3)
+	require.Nil(t, err, "could not parse wait strategy")
+	require.Equal(t, 3*time.Second, strategy.conditions[0].duration)
+
+	strategy, err = parseWaitStrategy("timeout:2s", 1)
+	require.Nil(t, err, "could not parse wait strategy")
+	require.Empty(t, strategy.conditions)
+
+	for _, value := range []string{"", "unknown", "selector:", "network-idle:-1", "timeout:abc", "dom-quiet:5"} {
+		_, err := parseWaitStrategy(value, 1)
+		require.NotNil(t, err, "invalid wait strategy %q was parsed", value)
+	}
+}
+
+func TestWaitStrategiesForURL(t *testing.T) {
+	options := &types.Options{
+		Timeout:           10,
+		TimeStable:        1,
+		HeadlessWait:      "network-idle",
+		HeadlessWaitRules: goflags.StringSlice{`/dashboard => selector:#data; timeout:20s`},
+	}
+	strategies, err := newWaitStrategies(options)
+	require.Nil(t, err, "could not create wait strategies")
+
+	require.Equal(t, waitSelector, strategies.forURL("https://example.com/dashboard/stats").conditions[0].kind)
+	require.Equal(t, waitNetworkIdle, strategies.forURL("https://example.com/").conditions[0].kind)
+
+	// stable waits are bounded by the page timeout
+	strategies, err = newWaitStrategies(&types.Options{Timeout: 4, TimeStable: 10})
+	require.Nil(t, err, "could not create wait strategies")
+	require.Equal(t, 2*time.Second, strategies.global.conditions[0].duration)
+
+	_, err = newWaitStrategies(&types.Options{HeadlessWaitRules: goflags.StringSlice{"/dashboard selector:#data"}})
+	require.NotNil(t, err, "invalid wait rule was parsed")
+}
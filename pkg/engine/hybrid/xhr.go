@@ -0,0 +1,72 @@
+package hybrid
+
+import (
+	"net/http"
+
+	"github.com/projectdiscovery/katana/pkg/engine/common"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+)
+
+const (
+	// xhrTag is the tag of the replayed xhr requests
+	xhrTag = "xhr"
+	// xhrAttribute is the attribute of the replayed xhr requests
+	xhrAttribute = "replay"
+)
+
+// xhrSkippedHeaders are the captured headers not replayed, they are
+// either set by the http client or by the shared cookie jar.
+var xhrSkippedHeaders = []string{"Cookie", "Content-Length", "Host", "Connection", "Accept-Encoding"}
+
+// replayXhrRequests enqueues the xhr requests made by the page so that their
+// responses are crawled. Requests are deduplicated on their method, url and
+// body shape, i.e. requests only differing by the values of their body are
+// replayed once. Only requests with safe methods are replayed unless unsafe
+// replay is enabled.
+func (c *Crawler) replayXhrRequests(s *common.CrawlSession, request *navigation.Request, depth int, xhrRequests []navigation.Request) {
+	var navigationRequests []*navigation.Request
+	for _, xhr := range xhrRequests {
+		method := xhr.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		if !isReplayable(method, c.Options.Options.XhrReplayUnsafe, c.Router == nil) {
+			continue
+		}
+		key := method + " " + xhr.URL + " " + utils.BodyShape(xhr.Body)
+		if _, replayed := c.replayedXhrs.LoadOrStore(key, struct{}{}); replayed {
+			continue
+		}
+
+		headers := make(map[string]string, len(xhr.Headers))
+		for name, value := range xhr.Headers {
+			if !stringsutil.EqualFoldAny(name, xhrSkippedHeaders...) {
+				headers[name] = value
+			}
+		}
+		navigationRequests = append(navigationRequests, &navigation.Request{
+			Method:       method,
+			URL:          xhr.URL,
+			Body:         xhr.Body,
+			Headers:      headers,
+			Depth:        depth,
+			RootHostname: s.Hostname,
+			Source:       request.URL,
+			Tag:          xhrTag,
+			Attribute:    xhrAttribute,
+		})
+	}
+	c.Enqueue(s.Queue, navigationRequests...)
+}
+
+// isReplayable returns true if a captured request with the method can be
+// replayed. Requests rendered in the browser in headless only mode can only
+// be get requests.
+func isReplayable(method string, unsafe, headlessOnly bool) bool {
+	if headlessOnly {
+		return method == http.MethodGet
+	}
+	return unsafe || navigation.IsSafeMethod(method)
+}
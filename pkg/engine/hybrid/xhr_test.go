@@ -0,0 +1,30 @@
+package hybrid
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsReplayable(t *testing.T) {
+	tests := []struct {
+		method       string
+		unsafe       bool
+		headlessOnly bool
+		expected     bool
+	}{
+		{method: http.MethodGet, expected: true},
+		{method: http.MethodHead, expected: true},
+		{method: http.MethodOptions, expected: true},
+		{method: http.MethodPost},
+		{method: http.MethodDelete},
+		{method: http.MethodPut, unsafe: true, expected: true},
+		{method: http.MethodGet, headlessOnly: true, expected: true},
+		{method: http.MethodHead, headlessOnly: true},
+		{method: http.MethodPost, unsafe: true, headlessOnly: true},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, isReplayable(test.method, test.unsafe, test.headlessOnly), "invalid replay of %s (unsafe: %v, headless only: %v)", test.method, test.unsafe, test.headlessOnly)
+	}
+}
@@ -0,0 +1,116 @@
+package login
+
+import (
+	"fmt"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
+	"github.com/projectdiscovery/katana/pkg/engine/profile"
+	"github.com/projectdiscovery/katana/pkg/types"
+	"github.com/projectdiscovery/utils/errkit"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+// LaunchBrowser launches a short-lived browser used to run the login flow
+// when headless crawling is disabled. The returned function releases it.
+func LaunchBrowser(options *types.Options) (*rod.Browser, func(), error) {
+	var (
+		controlURL     string
+		chromeLauncher *launcher.Launcher
+		err            error
+	)
+
+	if wsURLs := options.ParseChromeWSUrls(); len(wsURLs) > 0 {
+		controlURL = wsURLs[0]
+	} else {
+		chromeLauncher, err = NewChromeLauncher(options, nil, "")
+		if err != nil {
+			return nil, nil, errkit.Wrap(err, "login: could not create chrome launcher")
+		}
+
+		controlURL, err = chromeLauncher.Launch()
+		if err != nil {
+			return nil, nil, errkit.Wrap(err, "login: could not launch chrome")
+		}
+	}
+
+	browser := rod.New().ControlURL(controlURL)
+	if err := browser.Connect(); err != nil {
+		if chromeLauncher != nil {
+			chromeLauncher.Kill()
+		}
+		return nil, nil, errkit.Wrap(err, fmt.Sprintf("login: failed to connect to chrome instance at %s", controlURL))
+	}
+	incognito, err := browser.Incognito()
+	if err != nil {
+		if chromeLauncher != nil {
+			chromeLauncher.Kill()
+		}
+		return nil, nil, errkit.Wrap(err, "login: failed to create incognito browser")
+	}
+
+	release := func() {
+		_ = incognito.Close()
+		if chromeLauncher != nil {
+			_ = browser.Close()
+			chromeLauncher.Kill()
+			chromeLauncher.Cleanup()
+		}
+	}
+	return incognito, release, nil
+}
+
+// NewChromeLauncher returns a chrome launcher for the options, emulating the
+// window size and locale of the profile if any. The user data dir is a
+// temporary directory if dataStore is empty.
+func NewChromeLauncher(options *types.Options, browserProfile *profile.Profile, dataStore string) (*launcher.Launcher, error) {
+	width, height := 1080, 1920
+	if browserProfile != nil && browserProfile.Width > 0 {
+		width, height = browserProfile.Width, browserProfile.Height
+	}
+
+	chromeLauncher := launcher.New().
+		Leakless(true).
+		Headless(!options.ShowBrowser).
+		Set("disable-gpu", "true").
+		Set("ignore-certificate-errors", "true").
+		Set("ignore-certificate-errors", "1").
+		Set("disable-crash-reporter", "true").
+		Set("disable-notifications", "true").
+		Set("hide-scrollbars", "true").
+		Set("window-size", fmt.Sprintf("%d,%d", width, height)).
+		Set("mute-audio", "true").
+		Delete("use-mock-keychain")
+	if dataStore != "" {
+		chromeLauncher.UserDataDir(dataStore)
+	}
+
+	if options.SystemChromePath != "" {
+		chromeLauncher.Bin(options.SystemChromePath)
+	} else if options.UseInstalledChrome {
+		chromePath, hasChrome := launcher.LookPath()
+		if !hasChrome {
+			return nil, errkit.New("the chrome browser is not installed")
+		}
+		chromeLauncher.Bin(chromePath)
+	}
+
+	if browserProfile != nil && browserProfile.Locale != "" {
+		chromeLauncher.Set("lang", browserProfile.Locale)
+	}
+	if options.HeadlessNoSandbox {
+		chromeLauncher.Set("no-sandbox", "true")
+	}
+	if options.Proxy != "" {
+		proxyURL, err := urlutil.Parse(options.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		chromeLauncher.Set("proxy-server", proxyURL.String())
+	}
+	for k, v := range options.ParseHeadlessOptionalArguments() {
+		chromeLauncher.Set(flags.Flag(k), v)
+	}
+	return chromeLauncher, nil
+}
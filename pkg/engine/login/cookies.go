@@ -0,0 +1,66 @@
+package login
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// HTTPCookie converts a browser cookie to an http cookie. Host-only cookies
+// are returned without domain, as the domain attribute would widen them to
+// the subdomains.
+func HTTPCookie(cookie *proto.NetworkCookie) *http.Cookie {
+	httpCookie := &http.Cookie{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		Path:     cookie.Path,
+		Secure:   cookie.Secure,
+		HttpOnly: cookie.HTTPOnly,
+	}
+	// host-only cookies have a domain without a leading dot
+	if strings.HasPrefix(cookie.Domain, ".") {
+		httpCookie.Domain = cookie.Domain
+	}
+	if !cookie.Session && cookie.Expires > 0 {
+		httpCookie.Expires = cookie.Expires.Time()
+	}
+	switch cookie.SameSite {
+	case proto.NetworkCookieSameSiteStrict:
+		httpCookie.SameSite = http.SameSiteStrictMode
+	case proto.NetworkCookieSameSiteLax:
+		httpCookie.SameSite = http.SameSiteLaxMode
+	case proto.NetworkCookieSameSiteNone:
+		httpCookie.SameSite = http.SameSiteNoneMode
+	}
+	return httpCookie
+}
+
+// CookieParam converts an http cookie of the url to a browser cookie
+// parameter. Cookies without domain are set as host-only cookies of the url.
+func CookieParam(cookie *http.Cookie, URL string) *proto.NetworkCookieParam {
+	param := &proto.NetworkCookieParam{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		Path:     cookie.Path,
+		Secure:   cookie.Secure,
+		HTTPOnly: cookie.HttpOnly,
+	}
+	if cookie.Domain != "" {
+		param.Domain = "." + strings.TrimPrefix(cookie.Domain, ".")
+	} else {
+		param.URL = URL
+	}
+	if !cookie.Expires.IsZero() {
+		param.Expires = proto.TimeSinceEpoch(cookie.Expires.Unix())
+	}
+	switch cookie.SameSite {
+	case http.SameSiteStrictMode:
+		param.SameSite = proto.NetworkCookieSameSiteStrict
+	case http.SameSiteLaxMode:
+		param.SameSite = proto.NetworkCookieSameSiteLax
+	case http.SameSiteNoneMode:
+		param.SameSite = proto.NetworkCookieSameSiteNone
+	}
+	return param
+}
@@ -0,0 +1,27 @@
+package login
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCookieConversion(t *testing.T) {
+	expires := time.Unix(1893456000, 0)
+
+	httpCookie := HTTPCookie(&proto.NetworkCookie{Name: "sid", Value: "1", Domain: ".example.com", Path: "/app", Secure: true, HTTPOnly: true, SameSite: proto.NetworkCookieSameSiteLax, Expires: proto.TimeSinceEpoch(expires.Unix())})
+	require.Equal(t, &http.Cookie{Name: "sid", Value: "1", Domain: ".example.com", Path: "/app", Secure: true, HttpOnly: true, SameSite: http.SameSiteLaxMode, Expires: expires}, httpCookie)
+
+	// host-only and session cookies have neither domain nor expiry
+	httpCookie = HTTPCookie(&proto.NetworkCookie{Name: "csrf", Value: "2", Domain: "www.example.com", Path: "/", Session: true, Expires: -1})
+	require.Equal(t, &http.Cookie{Name: "csrf", Value: "2", Path: "/"}, httpCookie)
+
+	param := CookieParam(&http.Cookie{Name: "sid", Value: "1", Domain: "example.com", Path: "/app", Secure: true, SameSite: http.SameSiteStrictMode, Expires: expires}, "https://www.example.com/app/")
+	require.Equal(t, &proto.NetworkCookieParam{Name: "sid", Value: "1", Domain: ".example.com", Path: "/app", Secure: true, SameSite: proto.NetworkCookieSameSiteStrict, Expires: proto.TimeSinceEpoch(expires.Unix())}, param)
+
+	param = CookieParam(&http.Cookie{Name: "csrf", Value: "2", Path: "/account"}, "https://www.example.com/account/")
+	require.Equal(t, &proto.NetworkCookieParam{Name: "csrf", Value: "2", URL: "https://www.example.com/account/", Path: "/account"}, param)
+}
@@ -0,0 +1,137 @@
+package login
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/utils/errkit"
+)
+
+// storageJS returns the origin and web storage of the current document
+const storageJS = `() => JSON.stringify({
+	origin: location.origin,
+	local: Object.assign({}, window.localStorage),
+	session: Object.assign({}, window.sessionStorage),
+})`
+
+// Execute runs the login flow in a new page of the browser returning
+// the authenticated session. Each step is bounded by the timeout.
+func (f *Flow) Execute(browser *rod.Browser, timeout time.Duration) (*Session, error) {
+	page, err := browser.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		return nil, errkit.Wrap(err, "login: could not create target")
+	}
+	defer func() {
+		if err := page.Close(); err != nil {
+			gologger.Error().Msgf("Error closing page: %v\n", err)
+		}
+	}()
+
+	for i, step := range f.Steps {
+		if err := step.execute(page.Timeout(timeout)); err != nil {
+			return nil, errkit.Wrap(err, fmt.Sprintf("login: step %d (%s) failed", i+1, step.Action))
+		}
+	}
+
+	cookies, err := browser.GetCookies()
+	if err != nil {
+		return nil, errkit.Wrap(err, "login: could not get cookies")
+	}
+	session := &Session{Cookies: cookies}
+
+	result, err := page.Timeout(timeout).Eval(storageJS)
+	if err != nil {
+		return nil, errkit.Wrap(err, "login: could not get web storage")
+	}
+	var storage struct {
+		Origin  string            `json:"origin"`
+		Local   map[string]string `json:"local"`
+		Session map[string]string `json:"session"`
+	}
+	if err := json.Unmarshal([]byte(result.Value.Str()), &storage); err != nil {
+		return nil, errkit.Wrap(err, "login: could not decode web storage")
+	}
+	session.Origin = storage.Origin
+	session.LocalStorage = storage.Local
+	session.SessionStorage = storage.Session
+	return session, nil
+}
+
+// execute performs the step on the page
+func (s *Step) execute(page *rod.Page) error {
+	switch s.Action {
+	case Navigate:
+		if err := page.Navigate(s.URL); err != nil {
+			return err
+		}
+		return page.WaitLoad()
+	case Fill:
+		element, err := page.Element(s.Selector)
+		if err != nil {
+			return err
+		}
+		if err := element.SelectAllText(); err != nil {
+			return err
+		}
+		return element.Input(s.Value)
+	case Click:
+		element, err := page.Element(s.Selector)
+		if err != nil {
+			return err
+		}
+		return element.Click(proto.InputMouseButtonLeft, 1)
+	case Wait:
+		if s.Duration > 0 {
+			time.Sleep(time.Duration(s.Duration) * time.Second)
+		}
+		if s.Selector != "" {
+			if _, err := page.Element(s.Selector); err != nil {
+				return err
+			}
+		}
+		if s.urlRegex != nil {
+			if err := page.Wait(rod.Eval(`(r) => new RegExp(r).test(location.href)`, s.urlRegex.String())); err != nil {
+				return err
+			}
+		}
+		if s.Text != "" {
+			return page.Wait(rod.Eval(`(t) => document.body && document.body.innerText.includes(t)`, s.Text))
+		}
+		return nil
+	case Assert:
+		if s.Selector != "" {
+			has, _, err := page.Has(s.Selector)
+			if err != nil {
+				return err
+			}
+			if !has {
+				return errkit.Newf("selector %s not found", s.Selector)
+			}
+		}
+		if s.urlRegex != nil {
+			info, err := page.Info()
+			if err != nil {
+				return err
+			}
+			if !s.urlRegex.MatchString(info.URL) {
+				return errkit.Newf("url %s does not match %s", info.URL, s.urlRegex.String())
+			}
+		}
+		if s.Text != "" {
+			text, err := page.Eval(`() => document.body ? document.body.innerText : ""`)
+			if err != nil {
+				return err
+			}
+			if !strings.Contains(text.Value.Str(), s.Text) {
+				return errkit.Newf("text %q not found", s.Text)
+			}
+		}
+		return nil
+	}
+	return errkit.Newf("unknown action %q", s.Action)
+}
@@ -0,0 +1,157 @@
+// Package login implements declarative login flows which are executed
+// inside a browser to authenticate the crawl before it starts.
+package login
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/utils/errkit"
+	"gopkg.in/yaml.v3"
+)
+
+// Action is an action performed by a login flow step
+type Action string
+
+const (
+	// Navigate navigates the page to the step url
+	Navigate Action = "navigate"
+	// Fill fills the element matching the step selector with the step value
+	Fill Action = "fill"
+	// Click clicks the element matching the step selector
+	Click Action = "click"
+	// Wait waits for a selector, an url or a duration
+	Wait Action = "wait"
+	// Assert verifies that a selector, an url or a text is present
+	Assert Action = "assert"
+)
+
+// Flow is a declarative login sequence
+type Flow struct {
+	// Steps are the steps executed in order
+	Steps []Step `yaml:"steps"`
+	// Expiry contains the conditions used to detect an expired session
+	Expiry Expiry `yaml:"expiry,omitempty"`
+}
+
+// Step is a single step of a login flow
+type Step struct {
+	Action Action `yaml:"action"`
+	// URL is the url to navigate to or the url regex to wait for / assert
+	URL string `yaml:"url,omitempty"`
+	// Selector is the css selector of the element to act on
+	Selector string `yaml:"selector,omitempty"`
+	// Value is the value to fill, environment variables are expanded
+	Value string `yaml:"value,omitempty"`
+	// Text is the text expected in the page for assert steps
+	Text string `yaml:"text,omitempty"`
+	// Duration is the time to wait in seconds for wait steps
+	Duration int `yaml:"duration,omitempty"`
+
+	urlRegex *regexp.Regexp
+}
+
+// Expiry contains the conditions for detecting an expired session.
+// A response matching any of the conditions is considered expired.
+type Expiry struct {
+	// StatusCodes are the response status codes of an expired session
+	StatusCodes []int `yaml:"status-codes,omitempty"`
+	// Location is a regex matched against the redirect location
+	Location string `yaml:"location,omitempty"`
+	// Body is a list of strings which indicate an expired session
+	Body []string `yaml:"body,omitempty"`
+
+	locationRegex *regexp.Regexp
+}
+
+// LoadFlow loads and validates a login flow from a yaml file
+func LoadFlow(file string) (*Flow, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errkit.Wrap(err, "login: could not read login flow")
+	}
+	flow := &Flow{}
+	if err := yaml.Unmarshal(data, flow); err != nil {
+		return nil, errkit.Wrap(err, "login: could not decode login flow")
+	}
+	if err := flow.compile(); err != nil {
+		return nil, err
+	}
+	return flow, nil
+}
+
+// compile validates the flow and compiles its regexes
+func (f *Flow) compile() error {
+	if len(f.Steps) == 0 {
+		return errkit.New("login: no steps specified in login flow")
+	}
+	for i := range f.Steps {
+		step := &f.Steps[i]
+		switch step.Action {
+		case Navigate:
+			if step.URL == "" {
+				return errkit.Newf("login: step %d: navigate requires an url", i+1)
+			}
+		case Fill:
+			if step.Selector == "" {
+				return errkit.Newf("login: step %d: fill requires a selector", i+1)
+			}
+			step.Value = os.ExpandEnv(step.Value)
+		case Click:
+			if step.Selector == "" {
+				return errkit.Newf("login: step %d: click requires a selector", i+1)
+			}
+		case Wait, Assert:
+			if step.Selector == "" && step.URL == "" && step.Text == "" && step.Duration <= 0 {
+				return errkit.Newf("login: step %d: %s requires a selector, url, text or duration", i+1, step.Action)
+			}
+			if step.URL != "" {
+				compiled, err := regexp.Compile(step.URL)
+				if err != nil {
+					return errkit.Wrap(err, "login: invalid url regex")
+				}
+				step.urlRegex = compiled
+			}
+		default:
+			return errkit.Newf("login: step %d: unknown action %q", i+1, step.Action)
+		}
+	}
+	if f.Expiry.Location != "" {
+		compiled, err := regexp.Compile(f.Expiry.Location)
+		if err != nil {
+			return errkit.Wrap(err, "login: invalid expiry location regex")
+		}
+		f.Expiry.locationRegex = compiled
+	}
+	return nil
+}
+
+// IsExpired returns true if the response for the request indicates that the session has expired
+func (f *Flow) IsExpired(req *navigation.Request, resp *navigation.Response) bool {
+	if resp == nil || resp.Resp == nil {
+		return false
+	}
+	for _, statusCode := range f.Expiry.StatusCodes {
+		if resp.StatusCode == statusCode {
+			return true
+		}
+	}
+	if f.Expiry.locationRegex != nil {
+		if location := resp.Resp.Header.Get("Location"); location != "" && f.Expiry.locationRegex.MatchString(location) {
+			return true
+		}
+		// the final url is only considered when the login page itself was not requested
+		redirected := resp.Resp.Request != nil && resp.Resp.Request.URL != nil && !f.Expiry.locationRegex.MatchString(req.URL)
+		if redirected && f.Expiry.locationRegex.MatchString(resp.Resp.Request.URL.String()) {
+			return true
+		}
+	}
+	for _, value := range f.Expiry.Body {
+		if value != "" && strings.Contains(resp.Body, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package login
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	urlutil "github.com/projectdiscovery/utils/url"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFlow(t *testing.T) {
+	t.Setenv("KATANA_TEST_PASSWORD", "secret")
+
+	t.Run("valid", func(t *testing.T) {
+		flow, err := LoadFlow(writeFlow(t, `steps:
+  - action: navigate
+    url: https://example.com/login
+  - action: fill
+    selector: "#password"
+    value: $KATANA_TEST_PASSWORD
+  - action: click
+    selector: "button"
+  - action: wait
+    url: /dashboard
+expiry:
+  status-codes: [401]
+  location: /login
+`))
+		require.Nil(t, err, "could not load flow")
+		require.Len(t, flow.Steps, 4)
+		require.Equal(t, "secret", flow.Steps[1].Value, "could not expand env variable")
+		require.NotNil(t, flow.Steps[3].urlRegex, "could not compile url regex")
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := LoadFlow(writeFlow(t, `steps:
+  - action: click
+`))
+		require.NotNil(t, err, "click without selector should fail")
+
+		_, err = LoadFlow(writeFlow(t, `steps:
+  - action: hover
+    selector: a
+`))
+		require.NotNil(t, err, "unknown action should fail")
+	})
+}
+
+func TestFlowIsExpired(t *testing.T) {
+	flow := &Flow{
+		Steps: []Step{{Action: Navigate, URL: "https://example.com/login"}},
+		Expiry: Expiry{
+			StatusCodes: []int{401},
+			Location:    "/login",
+			Body:        []string{"session expired"},
+		},
+	}
+	require.Nil(t, flow.compile())
+
+	newResponse := func(finalURL string, statusCode int, header http.Header, body string) *navigation.Response {
+		parsed, _ := urlutil.Parse(finalURL)
+		return &navigation.Response{
+			Resp:       &http.Response{Request: &http.Request{URL: parsed.URL}, Header: header},
+			StatusCode: statusCode,
+			Body:       body,
+		}
+	}
+	req := &navigation.Request{Method: http.MethodGet, URL: "https://example.com/account"}
+
+	require.True(t, flow.IsExpired(req, newResponse("https://example.com/account", 401, http.Header{}, "")), "status code not detected")
+	require.True(t, flow.IsExpired(req, newResponse("https://example.com/account", 302, http.Header{"Location": []string{"/login?next=/account"}}, "")), "location not detected")
+	require.True(t, flow.IsExpired(req, newResponse("https://example.com/login", 200, http.Header{}, "")), "redirect to login not detected")
+	require.True(t, flow.IsExpired(req, newResponse("https://example.com/account", 200, http.Header{}, "your session expired")), "body not detected")
+	require.False(t, flow.IsExpired(req, newResponse("https://example.com/account", 200, http.Header{}, "welcome")), "valid session detected as expired")
+
+	loginReq := &navigation.Request{Method: http.MethodGet, URL: "https://example.com/login"}
+	require.False(t, flow.IsExpired(loginReq, newResponse("https://example.com/login", 200, http.Header{}, "")), "requested login page detected as expired")
+}
+
+func writeFlow(t *testing.T, content string) string {
+	file := filepath.Join(t.TempDir(), "flow.yaml")
+	require.Nil(t, os.WriteFile(file, []byte(content), 0600))
+	return file
+}
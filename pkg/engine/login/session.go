@@ -0,0 +1,74 @@
+package login
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Session is an authenticated browser session produced by a login flow
+type Session struct {
+	// Cookies are all the browser cookies after the login
+	Cookies []*proto.NetworkCookie
+	// Origin is the origin of the page the login finished on
+	Origin string
+	// LocalStorage is the localStorage of the origin
+	LocalStorage map[string]string
+	// SessionStorage is the sessionStorage of the origin
+	SessionStorage map[string]string
+}
+
+// SetCookies stores the session cookies into the cookie jar
+func (s *Session) SetCookies(jar http.CookieJar) {
+	for _, cookie := range s.Cookies {
+		scheme := "http"
+		if cookie.Secure {
+			scheme = "https"
+		}
+		cookieURL, err := url.Parse(fmt.Sprintf("%s://%s%s", scheme, strings.TrimPrefix(cookie.Domain, "."), cookie.Path))
+		if err != nil {
+			continue
+		}
+		jar.SetCookies(cookieURL, []*http.Cookie{HTTPCookie(cookie)})
+	}
+}
+
+// CookieParams returns the session cookies as browser cookie parameters
+func (s *Session) CookieParams() []*proto.NetworkCookieParam {
+	params := make([]*proto.NetworkCookieParam, 0, len(s.Cookies))
+	for _, cookie := range s.Cookies {
+		params = append(params, &proto.NetworkCookieParam{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HTTPOnly,
+			SameSite: cookie.SameSite,
+			Expires:  cookie.Expires,
+		})
+	}
+	return params
+}
+
+// StorageScript returns a script restoring the session web storage
+// which is evaluated on every new document of the session origin.
+func (s *Session) StorageScript() string {
+	if s.Origin == "" || (len(s.LocalStorage) == 0 && len(s.SessionStorage) == 0) {
+		return ""
+	}
+	origin, _ := jsoniter.MarshalToString(s.Origin)
+	local, _ := jsoniter.MarshalToString(s.LocalStorage)
+	session, _ := jsoniter.MarshalToString(s.SessionStorage)
+	return fmt.Sprintf(`(() => {
+	if (location.origin !== %s) return;
+	try {
+		for (const [k, v] of Object.entries(%s || {})) { if (localStorage.getItem(k) === null) localStorage.setItem(k, v); }
+		for (const [k, v] of Object.entries(%s || {})) { if (sessionStorage.getItem(k) === null) sessionStorage.setItem(k, v); }
+	} catch (e) {}
+})()`, origin, local, session)
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+)
+
+var (
+	// cssCommentRegex matches css comments
+	cssCommentRegex = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	// cssURLRegex matches url() functions with a quoted or unquoted url
+	cssURLRegex = regexp.MustCompile(`(?i)url\(\s*(?:"([^"]*)"|'([^']*)'|([^)"'\s]*))\s*\)`)
+	// cssImportRegex matches @import rules with a string instead of url()
+	cssImportRegex = regexp.MustCompile(`(?i)@import\s+(?:"([^"]*)"|'([^']*)')`)
+	// cssImageSetRegex matches the start of image-set() functions
+	cssImageSetRegex = regexp.MustCompile(`(?i)(?:-webkit-)?image-set\(`)
+	// cssStringRegex matches css strings
+	cssStringRegex = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+)
+
+// cssURL is a url found in a stylesheet with the construct it was found in
+type cssURL struct {
+	value     string
+	attribute string
+}
+
+// extractCSSURLs returns the urls of url() functions, @import rules and
+// image-set() candidates of the stylesheet.
+func extractCSSURLs(css string) []cssURL {
+	css = cssCommentRegex.ReplaceAllString(css, "")
+
+	var urls []cssURL
+	add := func(value, attribute string) {
+		value = strings.TrimSpace(value)
+		if value == "" || stringsutil.HasPrefixAnyI(value, "data:", "#", "about:", "javascript:") {
+			return
+		}
+		urls = append(urls, cssURL{value: value, attribute: attribute})
+	}
+
+	for _, match := range cssURLRegex.FindAllStringSubmatch(css, -1) {
+		add(match[1]+match[2]+match[3], "url")
+	}
+	for _, match := range cssImportRegex.FindAllStringSubmatch(css, -1) {
+		add(match[1]+match[2], "import")
+	}
+	// image-set() candidates may be plain strings instead of url() functions
+	for _, location := range cssImageSetRegex.FindAllStringIndex(css, -1) {
+		arguments := cssFunctionArguments(css[location[1]:])
+		for _, match := range cssStringRegex.FindAllStringSubmatch(cssURLRegex.ReplaceAllString(arguments, ""), -1) {
+			add(match[1]+match[2], "image-set")
+		}
+	}
+	return urls
+}
+
+// cssFunctionArguments returns the arguments of a function up to its closing parenthesis
+func cssFunctionArguments(css string) string {
+	depth := 0
+	for i, char := range css {
+		switch char {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return css[:i]
+			}
+			depth--
+		}
+	}
+	return css
+}
+
+// cssFileParser parses urls from stylesheet responses, relative
+// urls are resolved against the location of the stylesheet.
+func cssFileParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
+	contentType := resp.Resp.Header.Get("Content-Type")
+	if !strings.Contains(contentType, "text/css") && !strings.HasSuffix(strings.ToLower(resp.Resp.Request.URL.Path), ".css") {
+		return
+	}
+	for _, item := range extractCSSURLs(resp.Body) {
+		navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(item.value, resp.Resp.Request.URL.String(), "css", item.attribute, resp))
+	}
+	return
+}
+
+// cssInlineParser parses urls from <style> blocks and style attributes
+func cssInlineParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
+	resp.Reader.Find("style").Each(func(i int, item *goquery.Selection) {
+		for _, cssItem := range extractCSSURLs(item.Text()) {
+			navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(cssItem.value, resp.Resp.Request.URL.String(), "style", cssItem.attribute, resp))
+		}
+	})
+	resp.Reader.Find("[style]").Each(func(i int, item *goquery.Selection) {
+		style, _ := item.Attr("style")
+		for _, cssItem := range extractCSSURLs(style) {
+			navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(cssItem.value, resp.Resp.Request.URL.String(), goquery.NodeName(item), "style", resp))
+		}
+	})
+	return
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	urlutil "github.com/projectdiscovery/utils/url"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSSParsers(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		parsed, _ := urlutil.Parse("https://security-crawl-maze.app/css/font-face.css")
+		body := `/* url(/commented.found) */
+@import "imported.css";
+@import url('/css/print.css') print;
+@font-face {
+	font-family: "maze";
+	src: url(/css/font-face.found) format("woff"), url("data:font/woff;base64,AAAA");
+}
+.hero { background: image-set("hero-1x.png" 1x, url(hero-2x.png) 2x); }`
+		resp := &navigation.Response{Resp: &http.Response{Request: &http.Request{URL: parsed.URL}, Header: http.Header{"Content-Type": []string{"text/css"}}}, Body: body}
+		navigationRequests := cssFileParser(resp)
+
+		var urls []string
+		for _, request := range navigationRequests {
+			urls = append(urls, request.URL)
+		}
+		require.ElementsMatch(t, []string{
+			"https://security-crawl-maze.app/css/imported.css",
+			"https://security-crawl-maze.app/css/print.css",
+			"https://security-crawl-maze.app/css/font-face.found",
+			"https://security-crawl-maze.app/css/hero-1x.png",
+			"https://security-crawl-maze.app/css/hero-2x.png",
+		}, urls)
+
+		resp.Resp.Header.Set("Content-Type", "text/html")
+		resp.Resp.Request.URL.Path = "/index.html"
+		require.Empty(t, cssFileParser(resp), "non css response was parsed")
+	})
+
+	t.Run("inline", func(t *testing.T) {
+		parsed, _ := urlutil.Parse("https://security-crawl-maze.app/html/")
+		documentReader, _ := goquery.NewDocumentFromReader(strings.NewReader(`<style>body { background: url("/test/style.found") }</style><div style="background-image: url('background.found')"></div>`))
+		resp := &navigation.Response{Resp: &http.Response{Request: &http.Request{URL: parsed.URL}}, Reader: documentReader}
+		navigationRequests := cssInlineParser(resp)
+		require.Len(t, navigationRequests, 2)
+		require.Equal(t, "https://security-crawl-maze.app/test/style.found", navigationRequests[0].URL)
+		require.Equal(t, "style", navigationRequests[0].Tag)
+		require.Equal(t, "https://security-crawl-maze.app/html/background.found", navigationRequests[1].URL)
+		require.Equal(t, "div", navigationRequests[1].Tag)
+		require.Equal(t, "style", navigationRequests[1].Attribute)
+	})
+}
@@ -0,0 +1,401 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+)
+
+// The custom fields holding the metadata of the parsed documents
+const (
+	documentAuthorField   = "document_author"
+	documentProducerField = "document_producer"
+	documentCompanyField  = "document_company"
+	documentPathField     = "document_path"
+)
+
+// documentMaxPartSize is the maximum size of an inflated pdf stream or office part
+const documentMaxPartSize = 16 * 1024 * 1024
+
+var (
+	// pdfLinkRegex matches the uri actions and file specifications of pdf documents
+	pdfLinkRegex = regexp.MustCompile(`/(URI|F|UF)\s*[(<]`)
+	// pdfInfoRegex matches the entries of the pdf info dictionary
+	pdfInfoRegex = regexp.MustCompile(`/(Author|Creator|Producer)\s*[(<]`)
+	// pdfStringRegex matches pdf literal strings, such as the text of content streams
+	pdfStringRegex = regexp.MustCompile(`\((?:\\.|[^\\()])*\)`)
+	// xmpRegex matches the metadata of xmp packets
+	xmpRegex = regexp.MustCompile(`(?s)<(dc:creator|xmp:CreatorTool|pdf:Producer)\b[^>]*>(.*?)</(?:dc:creator|xmp:CreatorTool|pdf:Producer)>`)
+	// xmlTagRegex matches the tags nested in xmp metadata
+	xmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+	// internalPathRegex matches windows drive and unc paths
+	internalPathRegex = regexp.MustCompile(`(?i)(?:\b[a-z]:\\|\\\\[a-z0-9_.$-]+\\)[^\s"'<>|*?\x00-\x1f]*`)
+)
+
+// pdfMetadataFields are the fields of the pdf info and xmp metadata entries
+var pdfMetadataFields = map[string]string{
+	"Author": documentAuthorField, "Creator": documentProducerField, "Producer": documentProducerField,
+	"dc:creator": documentAuthorField, "xmp:CreatorTool": documentProducerField, "pdf:Producer": documentProducerField,
+}
+
+// officeMetadataFields are the fields of the office core and app properties
+var officeMetadataFields = map[string]string{
+	"creator": documentAuthorField, "lastModifiedBy": documentAuthorField,
+	"Application": documentProducerField, "Company": documentCompanyField, "Manager": documentAuthorField,
+}
+
+// documentLink is a link found in a document with the construct it was found in
+type documentLink struct {
+	value     string
+	attribute string
+}
+
+// documentData are the links and metadata fields of a document
+type documentData struct {
+	links  []documentLink
+	fields map[string][]string
+	seen   map[string]struct{}
+}
+
+func newDocumentData() *documentData {
+	return &documentData{fields: make(map[string][]string), seen: make(map[string]struct{})}
+}
+
+// addLink adds a link of the document, links to internal paths are added
+// to the path field instead.
+func (d *documentData) addLink(value, attribute string) {
+	value = strings.TrimSpace(value)
+	if isInternalPath(value) {
+		d.addField(documentPathField, value)
+		return
+	}
+	if value == "" || stringsutil.HasPrefixAnyI(value, "#", "javascript:", "data:", "mailto:", "tel:") {
+		return
+	}
+	if _, ok := d.seen[value]; ok {
+		return
+	}
+	d.seen[value] = struct{}{}
+	d.links = append(d.links, documentLink{value: value, attribute: attribute})
+}
+
+// addField adds a metadata value of the document
+func (d *documentData) addField(name, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	key := name + ":" + value
+	if _, ok := d.seen[key]; ok {
+		return
+	}
+	d.seen[key] = struct{}{}
+	d.fields[name] = append(d.fields[name], value)
+}
+
+// addText adds the urls and internal paths found in the text of the document
+func (d *documentData) addText(text string) {
+	for _, value := range utils.ExtractURLLikeValues(text) {
+		d.addLink(value, "text")
+	}
+	for _, value := range internalPathRegex.FindAllString(text, -1) {
+		d.addField(documentPathField, strings.TrimRight(value, ".,;)"))
+	}
+}
+
+// isInternalPath returns true if the value is a local file or network share path
+func isInternalPath(value string) bool {
+	if stringsutil.HasPrefixI(value, "file:") {
+		return true
+	}
+	location := internalPathRegex.FindStringIndex(value)
+	return location != nil && location[0] == 0
+}
+
+// documentContentParser parses the hyperlinks, embedded urls and metadata of
+// pdf and office documents, the metadata is output as custom fields of the
+// document url without requesting it again.
+func documentContentParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
+	// truncated documents can't be parsed
+	if resp.Truncated {
+		return
+	}
+	documentType := utils.DocumentType(resp.Resp.Header.Get("Content-Type"), resp.Resp.Request.URL.Path)
+
+	var document *documentData
+	switch {
+	case documentType == ".pdf" && strings.Contains(resp.Body[:min(len(resp.Body), 1024)], "%PDF-"):
+		document = parsePDFDocument(resp.Body)
+	case documentType != "" && documentType != ".pdf" && strings.HasPrefix(resp.Body, "PK\x03\x04"):
+		document = parseOfficeDocument(resp.Body)
+	default:
+		return
+	}
+
+	tag := strings.TrimPrefix(documentType, ".")
+	for _, link := range document.links {
+		navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(link.value, resp.Resp.Request.URL.String(), tag, link.attribute, resp))
+	}
+	if len(document.fields) != 0 {
+		navigationRequests = append(navigationRequests, &navigation.Request{
+			Method:       http.MethodGet,
+			URL:          resp.Resp.Request.URL.String(),
+			Depth:        resp.Depth,
+			RootHostname: resp.RootHostname,
+			CustomFields: document.fields,
+			SkipRequest:  true,
+		})
+	}
+	return
+}
+
+// parsePDFDocument parses the uri actions, file specifications, embedded
+// urls and metadata of a pdf document, including its deflated streams.
+func parsePDFDocument(body string) *documentData {
+	document := newDocumentData()
+	for _, content := range pdfContents(body) {
+		for _, match := range pdfLinkRegex.FindAllStringSubmatchIndex(content, -1) {
+			value, ok := readPDFString(content, match[1]-1)
+			if !ok {
+				continue
+			}
+			if content[match[2]:match[3]] == "URI" {
+				document.addLink(value, "uri")
+			} else {
+				document.addLink(value, "file")
+			}
+		}
+		for _, match := range pdfInfoRegex.FindAllStringSubmatchIndex(content, -1) {
+			if value, ok := readPDFString(content, match[1]-1); ok {
+				document.addField(pdfMetadataFields[content[match[2]:match[3]]], value)
+			}
+		}
+		for _, match := range xmpRegex.FindAllStringSubmatch(content, -1) {
+			document.addField(pdfMetadataFields[match[1]], xmlTagRegex.ReplaceAllString(match[2], " "))
+		}
+		for _, location := range pdfStringRegex.FindAllStringIndex(content, -1) {
+			if value, ok := readPDFString(content, location[0]); ok {
+				document.addText(value)
+			}
+		}
+	}
+	return document
+}
+
+// pdfContents returns the pdf document followed by its inflated streams
+func pdfContents(body string) []string {
+	contents := []string{body}
+	for offset := 0; ; {
+		index := strings.Index(body[offset:], "stream")
+		if index == -1 {
+			break
+		}
+		start := offset + index + len("stream")
+		offset = start
+		if strings.HasSuffix(body[:start], "endstream") {
+			continue
+		}
+		start += len(body[start:]) - len(strings.TrimLeft(body[start:], "\r\n"))
+		end := strings.Index(body[start:], "endstream")
+		if end == -1 {
+			break
+		}
+		offset = start + end + len("endstream")
+
+		reader, err := zlib.NewReader(strings.NewReader(body[start : start+end]))
+		if err != nil {
+			continue
+		}
+		// truncated streams are kept up to their corrupted data
+		data, _ := io.ReadAll(io.LimitReader(reader, documentMaxPartSize))
+		_ = reader.Close()
+		if len(data) > 0 {
+			contents = append(contents, string(data))
+		}
+	}
+	return contents
+}
+
+// readPDFString reads the literal or hex string starting at index
+func readPDFString(content string, index int) (string, bool) {
+	if content[index] == '<' {
+		end := strings.IndexByte(content[index:], '>')
+		// dictionaries such as file specifications are not strings
+		if end == -1 || strings.HasPrefix(content[index:], "<<") {
+			return "", false
+		}
+		digits := strings.Join(strings.Fields(content[index+1:index+end]), "")
+		if len(digits)%2 != 0 {
+			digits += "0"
+		}
+		decoded, err := hex.DecodeString(digits)
+		if err != nil {
+			return "", false
+		}
+		return decodePDFText(decoded), true
+	}
+
+	var value []byte
+	depth := 0
+	for i := index + 1; i < len(content); i++ {
+		char := content[i]
+		switch char {
+		case '\\':
+			i++
+			if i >= len(content) {
+				return decodePDFText(value), true
+			}
+			switch escaped := content[i]; escaped {
+			case 'n':
+				value = append(value, '\n')
+			case 'r':
+				value = append(value, '\r')
+			case 't':
+				value = append(value, '\t')
+			case 'b', 'f', '\r', '\n':
+				// backspaces, form feeds and escaped line breaks are dropped
+			default:
+				if escaped >= '0' && escaped <= '7' {
+					octal := 0
+					for j := 0; j < 3 && i < len(content) && content[i] >= '0' && content[i] <= '7'; j++ {
+						octal = octal*8 + int(content[i]-'0')
+						i++
+					}
+					i--
+					value = append(value, byte(octal))
+					continue
+				}
+				value = append(value, escaped)
+			}
+		case '(':
+			depth++
+			value = append(value, char)
+		case ')':
+			if depth == 0 {
+				return decodePDFText(value), true
+			}
+			depth--
+			value = append(value, char)
+		default:
+			value = append(value, char)
+		}
+	}
+	return decodePDFText(value), true
+}
+
+// decodePDFText decodes pdf text strings, utf-16 when they start with a byte order mark
+func decodePDFText(value []byte) string {
+	if len(value) < 2 || value[0] != 0xfe || value[1] != 0xff {
+		return string(value)
+	}
+	units := make([]uint16, 0, len(value)/2)
+	for i := 2; i+1 < len(value); i += 2 {
+		units = append(units, uint16(value[i])<<8|uint16(value[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// officeRelationships are the relationships of an office open xml part
+type officeRelationships struct {
+	Relationships []struct {
+		Type       string `xml:"Type,attr"`
+		Target     string `xml:"Target,attr"`
+		TargetMode string `xml:"TargetMode,attr"`
+	} `xml:"Relationship"`
+}
+
+// parseOfficeDocument parses the external relationships, embedded urls and
+// properties of an office open xml document (docx, xlsx or pptx).
+func parseOfficeDocument(body string) *documentData {
+	document := newDocumentData()
+	reader, err := zip.NewReader(strings.NewReader(body), int64(len(body)))
+	if err != nil {
+		return document
+	}
+	for _, file := range reader.File {
+		if !strings.HasSuffix(file.Name, ".xml") && !strings.HasSuffix(file.Name, ".rels") {
+			continue
+		}
+		data, err := readZipFile(file)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(file.Name, ".rels"):
+			var relationships officeRelationships
+			if err := xml.Unmarshal(data, &relationships); err != nil {
+				continue
+			}
+			for _, relationship := range relationships.Relationships {
+				if relationship.TargetMode != "External" {
+					continue
+				}
+				if strings.HasSuffix(relationship.Type, "/hyperlink") {
+					document.addLink(relationship.Target, "hyperlink")
+				} else {
+					document.addLink(relationship.Target, "relationship")
+				}
+			}
+		case file.Name == "docProps/core.xml" || file.Name == "docProps/app.xml":
+			walkXMLText(data, func(element, text string) {
+				if field, ok := officeMetadataFields[element]; ok {
+					document.addField(field, text)
+				} else {
+					document.addText(text)
+				}
+			})
+		default:
+			walkXMLText(data, func(element, text string) {
+				document.addText(text)
+			})
+		}
+	}
+	return document
+}
+
+// readZipFile reads a file of a zip archive up to the maximum part size
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	return io.ReadAll(io.LimitReader(reader, documentMaxPartSize))
+}
+
+// walkXMLText calls fn with the text of each element of the xml document,
+// attributes are skipped as they only hold namespaces and formatting.
+func walkXMLText(data []byte, fn func(element, text string)) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	var element string
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			element = t.Name.Local
+		case xml.CharData:
+			if text := strings.TrimSpace(string(t)); text != "" {
+				fn(element, text)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"testing"
+
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentContentParser(t *testing.T) {
+	parse := func(resp *navigation.Response) (map[string]string, map[string][]string) {
+		links := make(map[string]string)
+		var fields map[string][]string
+		for _, request := range documentContentParser(resp) {
+			// the metadata is output without requesting the document again
+			if request.CustomFields != nil {
+				require.True(t, request.SkipRequest)
+				require.Equal(t, resp.Resp.Request.URL.String(), request.URL)
+				fields = request.CustomFields
+				continue
+			}
+			require.False(t, request.SkipRequest)
+			links[request.URL] = request.Tag + ":" + request.Attribute
+		}
+		return links, fields
+	}
+
+	t.Run("pdf", func(t *testing.T) {
+		var stream bytes.Buffer
+		writer := zlib.NewWriter(&stream)
+		_, _ = writer.Write([]byte(`<< /Type /Annot /Subtype /Link /A << /S /URI /URI (https://intranet.example.com/wiki\(1\)) >> >>`))
+		_ = writer.Close()
+
+		body := "%PDF-1.7\n" +
+			"1 0 obj << /Type /Annot /A << /S /URI /URI (https://example.com/docs/guide) >> >> endobj\n" +
+			"2 0 obj << /Type /Filespec /F (annex.pdf) /UF (\\\\\\\\fileserver\\\\share\\\\annex.pdf) >> endobj\n" +
+			"3 0 obj << /Length 10 /Filter /FlateDecode >>\nstream\n" + stream.String() + "\nendstream\nendobj\n" +
+			"4 0 obj << /Author <FEFF004A0061006E0065> /Producer (Acme PDF Library 2.1) /Creator (Microsoft Word) >> endobj\n" +
+			"<x:xmpmeta><dc:creator><rdf:Seq><rdf:li>jdoe</rdf:li></rdf:Seq></dc:creator></x:xmpmeta>\n" +
+			"%%EOF"
+
+		links, fields := parse(newTestResponse("https://example.com/files/report.pdf", "application/pdf", body))
+		require.Equal(t, map[string]string{
+			"https://example.com/docs/guide":       "pdf:uri",
+			"https://example.com/files/annex.pdf":  "pdf:file",
+			"https://intranet.example.com/wiki(1)": "pdf:uri",
+		}, links)
+		require.Equal(t, map[string][]string{
+			documentAuthorField:   {"Jane", "jdoe"},
+			documentProducerField: {"Acme PDF Library 2.1", "Microsoft Word"},
+			documentPathField:     {`\\fileserver\share\annex.pdf`},
+		}, fields)
+
+		// documents over the size cap are not parsed
+		truncated := newTestResponse("https://example.com/files/report.pdf", "application/pdf", body)
+		truncated.Truncated = true
+		require.Empty(t, documentContentParser(truncated))
+	})
+
+	t.Run("docx", func(t *testing.T) {
+		var archive bytes.Buffer
+		writer := zip.NewWriter(&archive)
+		for name, content := range map[string]string{
+			"word/_rels/document.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+	<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://portal.example.com/login" TargetMode="External"/>
+	<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+	<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/attachedTemplate" Target="file:///C:\Users\jdoe\Templates\Report.dotx" TargetMode="External"/>
+</Relationships>`,
+			"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+	<w:p><w:r><w:t>See https://example.com/changelog and \\nas01\finance\q3.xlsx</w:t></w:r></w:p>
+</w:body></w:document>`,
+			"docProps/core.xml": `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">
+	<dc:creator>Jane Doe</dc:creator><cp:lastModifiedBy>jdoe</cp:lastModifiedBy>
+</cp:coreProperties>`,
+			"docProps/app.xml": `<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"><Application>Microsoft Office Word</Application><Company>Example Corp</Company></Properties>`,
+		} {
+			file, _ := writer.Create(name)
+			_, _ = file.Write([]byte(content))
+		}
+		_ = writer.Close()
+
+		links, fields := parse(newTestResponse("https://example.com/files/report.docx", "application/octet-stream", archive.String()))
+		require.Equal(t, map[string]string{
+			"https://portal.example.com/login": "docx:hyperlink",
+			"https://example.com/changelog":    "docx:text",
+		}, links)
+		require.ElementsMatch(t, []string{"Jane Doe", "jdoe"}, fields[documentAuthorField])
+		require.Equal(t, []string{"Microsoft Office Word"}, fields[documentProducerField])
+		require.Equal(t, []string{"Example Corp"}, fields[documentCompanyField])
+		require.ElementsMatch(t, []string{`file:///C:\Users\jdoe\Templates\Report.dotx`, `\\nas01\finance\q3.xlsx`}, fields[documentPathField])
+	})
+
+	t.Run("not a document", func(t *testing.T) {
+		links, fields := parse(newTestResponse("https://example.com/files/report.pdf", "text/html", "<html>%PDF-</html>"))
+		require.Empty(t, links)
+		require.Empty(t, fields)
+	})
+}
@@ -0,0 +1,98 @@
+package files
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// maxGraphQLProbeSize is the maximum size of a probe response read while probing
+const maxGraphQLProbeSize = 1024 * 1024
+
+// graphQLPaths are the common paths of graphql endpoints
+var graphQLPaths = []string{
+	"/graphql", "/api/graphql", "/v1/graphql", "/api/v1/graphql", "/graphql/v1", "/gql", "/query", "/graphql/console",
+}
+
+// graphQLErrorMarkers are the markers of the error messages returned by graphql
+// servers, telling graphql errors apart from the errors of other json apis.
+var graphQLErrorMarkers = []string{
+	"Cannot query field", "Syntax Error", "__typename", "Must provide query string", "GraphQL", "introspection",
+}
+
+type graphQLCrawler struct {
+	httpclient *retryablehttp.Client
+}
+
+// Visit probes the common graphql paths of the provided URL for endpoints
+func (r *graphQLCrawler) Visit(URL string) (navigationRequests []*navigation.Request, err error) {
+	URL = strings.TrimSuffix(URL, "/")
+	for _, path := range graphQLPaths {
+		requestURL := URL + path
+		resp, err := r.request(requestURL)
+		if err != nil || resp == nil {
+			continue
+		}
+		navResp := &navigation.Response{
+			Depth:      2,
+			Resp:       resp,
+			StatusCode: resp.StatusCode,
+			Headers:    utils.FlattenHeaders(resp.Header),
+		}
+		// introspection responses are parsed by the graphql parsers
+		request := navigation.NewNavigationRequestURLFromResponse(requestURL, requestURL, "file", "graphql", navResp)
+		request.Method = http.MethodPost
+		request.Body = utils.GraphQLRequestBody(utils.GraphQLIntrospectionQuery)
+		request.Headers = map[string]string{"Content-Type": "application/json"}
+		navigationRequests = append(navigationRequests, request)
+	}
+	return navigationRequests, nil
+}
+
+// request sends a typename query to a common path, returning the response if
+// it is a graphql response, i.e. with a typename or graphql errors.
+func (r *graphQLCrawler) request(requestURL string) (*http.Response, error) {
+	req, err := retryablehttp.NewRequest(http.MethodPost, requestURL, utils.GraphQLRequestBody("query { __typename }"))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", utils.WebUserAgent())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpclient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxGraphQLProbeSize))
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := jsoniter.Unmarshal(body, &response); err != nil {
+		return nil, nil
+	}
+	if _, ok := response.Data["__typename"]; ok {
+		return resp, nil
+	}
+	for _, graphQLError := range response.Errors {
+		for _, marker := range graphQLErrorMarkers {
+			if strings.Contains(graphQLError.Message, marker) {
+				return resp, nil
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,41 @@
+package files
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLVisit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/graphql" && r.Method == http.MethodPost:
+			_, _ = w.Write([]byte(`{"data": {"__typename": "Query"}}`))
+		case r.URL.Path == "/gql":
+			// graphql servers rejecting the query still return graphql errors
+			_, _ = w.Write([]byte(`{"errors": [{"message": "Cannot query field \"__typename\" on type \"Query\"."}]}`))
+		case r.URL.Path == "/query":
+			// json apis which are not graphql endpoints
+			_, _ = w.Write([]byte(`{"results": []}`))
+		case r.URL.Path == "/v1/graphql":
+			// rest apis returning errors
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors": [{"message": "invalid request body"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	crawler := &graphQLCrawler{httpclient: retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)}
+	navigationRequests, err := crawler.Visit(server.URL)
+	require.Nil(t, err)
+	require.Len(t, navigationRequests, 2)
+	require.Equal(t, server.URL+"/api/graphql", navigationRequests[0].URL)
+	require.Equal(t, server.URL+"/gql", navigationRequests[1].URL)
+	require.Equal(t, http.MethodPost, navigationRequests[0].Method)
+	require.Contains(t, navigationRequests[0].Body, "IntrospectionQuery")
+}
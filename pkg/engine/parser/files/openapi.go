@@ -0,0 +1,74 @@
+package files
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// maxSpecSize is the maximum size of a specification read while probing
+const maxSpecSize = 10 * 1024 * 1024
+
+// openAPIPaths are the well-known paths of OpenAPI and Swagger specifications
+var openAPIPaths = []string{
+	"/openapi.json", "/openapi.yaml", "/swagger.json", "/swagger.yaml",
+	"/v2/api-docs", "/v3/api-docs", "/api-docs", "/swagger/v1/swagger.json", "/swagger/doc.json",
+	"/api/openapi.json", "/api/swagger.json", "/api/v1/openapi.json", "/api/v1/swagger.json",
+}
+
+type openAPICrawler struct {
+	httpclient *retryablehttp.Client
+}
+
+// Visit probes the well-known paths of the provided URL for specifications
+func (r *openAPICrawler) Visit(URL string) (navigationRequests []*navigation.Request, err error) {
+	URL = strings.TrimSuffix(URL, "/")
+	for _, path := range openAPIPaths {
+		requestURL := URL + path
+		resp, err := r.request(requestURL)
+		if err != nil || resp == nil {
+			continue
+		}
+		navResp := &navigation.Response{
+			Depth:      2,
+			Resp:       resp,
+			StatusCode: resp.StatusCode,
+			Headers:    utils.FlattenHeaders(resp.Header),
+		}
+		// specifications are expanded once crawled by the openapi parser
+		navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(requestURL, requestURL, "file", "openapi", navResp))
+	}
+	return navigationRequests, nil
+}
+
+// request requests a well-known path, returning the response if it is a specification
+func (r *openAPICrawler) request(requestURL string) (*http.Response, error) {
+	req, err := retryablehttp.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", utils.WebUserAgent())
+
+	resp, err := r.httpclient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSpecSize))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := utils.ParseOpenAPI(string(body)); err != nil {
+		return nil, nil
+	}
+	return resp, nil
+}
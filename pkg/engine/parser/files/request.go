@@ -3,6 +3,7 @@ package files
 import (
 	"github.com/projectdiscovery/katana/pkg/navigation"
 	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/projectdiscovery/utils/errkit"
 )
 
 type visitFunc func(URL string) ([]*navigation.Request, error)
@@ -12,8 +13,9 @@ type KnownFiles struct {
 	httpclient *retryablehttp.Client
 }
 
-// New returns a new known files parser instance
-func New(httpclient *retryablehttp.Client, files string) *KnownFiles {
+// New returns a new known files parser instance, the openapi specifications and
+// graphql endpoints are only probed by default when their expansion is enabled.
+func New(httpclient *retryablehttp.Client, files string, openAPI, graphQL bool) *KnownFiles {
 	parser := &KnownFiles{
 		httpclient: httpclient,
 	}
@@ -24,23 +26,43 @@ func New(httpclient *retryablehttp.Client, files string) *KnownFiles {
 	case "sitemapxml":
 		crawler := &sitemapXmlCrawler{httpclient: httpclient}
 		parser.parsers = append(parser.parsers, crawler.Visit)
+	case "openapi":
+		crawler := &openAPICrawler{httpclient: httpclient}
+		parser.parsers = append(parser.parsers, crawler.Visit)
+	case "graphql":
+		crawler := &graphQLCrawler{httpclient: httpclient}
+		parser.parsers = append(parser.parsers, crawler.Visit)
 	default:
 		crawler := &robotsTxtCrawler{httpclient: httpclient}
 		parser.parsers = append(parser.parsers, crawler.Visit)
 		another := &sitemapXmlCrawler{httpclient: httpclient}
 		parser.parsers = append(parser.parsers, another.Visit)
+		if openAPI {
+			openAPICrawler := &openAPICrawler{httpclient: httpclient}
+			parser.parsers = append(parser.parsers, openAPICrawler.Visit)
+		}
+		if graphQL {
+			graphQLCrawler := &graphQLCrawler{httpclient: httpclient}
+			parser.parsers = append(parser.parsers, graphQLCrawler.Visit)
+		}
 	}
 	return parser
 }
 
-// Request requests all known files with visitors
+// Request requests all known files with visitors, a failing visitor
+// does not prevent the others from running.
 func (k *KnownFiles) Request(URL string) (navigationRequests []*navigation.Request, err error) {
+	var errs []error
 	for _, visitor := range k.parsers {
 		navRequests, err := visitor(URL)
 		if err != nil {
-			return navigationRequests, err
+			errs = append(errs, err)
+			continue
 		}
 		navigationRequests = append(navigationRequests, navRequests...)
 	}
+	if len(errs) > 0 {
+		err = errkit.Join(errs...)
+	}
 	return
 }
@@ -0,0 +1,16 @@
+package files
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKnownFiles(t *testing.T) {
+	require.Len(t, New(nil, "all", false, false).parsers, 2, "openapi specifications and graphql endpoints should not be probed without expansion")
+	require.Len(t, New(nil, "all", true, false).parsers, 3)
+	require.Len(t, New(nil, "all", false, true).parsers, 3)
+	require.Len(t, New(nil, "all", true, true).parsers, 4)
+	require.Len(t, New(nil, "openapi", true, false).parsers, 1)
+	require.Len(t, New(nil, "graphql", false, true).parsers, 1)
+}
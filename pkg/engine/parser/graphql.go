@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+)
+
+var (
+	// graphQLEndpointRegex matches the quoted graphql endpoint urls and paths of pages and scripts
+	graphQLEndpointRegex = regexp.MustCompile("(?i)[\"'`]((?:https?:)?(?://[^\\s\"'`<>/]+)?/(?:[^\\s\"'`<>?#]*/)?(?:graphql|gql)(?:/[^\\s\"'`<>?#]*)?)(?:[?#][^\\s\"'`<>]*)?[\"'`]")
+	// graphQLPathRegex matches the paths of graphql endpoints
+	graphQLPathRegex = regexp.MustCompile(`(?i)/(?:graphql|gql)(?:[/?#]|$)`)
+	// graphQLDocumentRegex matches the start of a graphql query or mutation document
+	graphQLDocumentRegex = regexp.MustCompile(`^\s*(?:query|mutation|\{)`)
+)
+
+// graphQLEndpointParser detects graphql endpoints from the references of pages
+// and scripts, the captured xhr requests and the crawled urls, and requests
+// their introspection.
+func graphQLEndpointParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
+	source := resp.Resp.Request.URL.String()
+	if resp.Resp.Request.Method == http.MethodGet && graphQLPathRegex.MatchString(resp.Resp.Request.URL.Path) {
+		navigationRequests = append(navigationRequests, newGraphQLRequest(graphQLEndpoint(source), source, utils.GraphQLIntrospectionQuery, "introspection", resp))
+	}
+	for _, match := range graphQLEndpointRegex.FindAllStringSubmatch(resp.Body, -1) {
+		navigationRequests = append(navigationRequests, newGraphQLRequest(match[1], source, utils.GraphQLIntrospectionQuery, "introspection", resp))
+	}
+	for _, xhr := range resp.XhrRequests {
+		if isGraphQLRequest(xhr) {
+			navigationRequests = append(navigationRequests, newGraphQLRequest(graphQLEndpoint(xhr.URL), source, utils.GraphQLIntrospectionQuery, "xhr", resp))
+		}
+	}
+	return
+}
+
+// graphQLOperationsParser outputs the operations of graphql endpoints
+// without requesting them.
+func graphQLOperationsParser(resp *navigation.Response) []*navigation.Request {
+	return parseGraphQLResponse(resp, false)
+}
+
+// graphQLQueriesParser outputs the operations of graphql endpoints and
+// requests their read-only queries.
+func graphQLQueriesParser(resp *navigation.Response) []*navigation.Request {
+	return parseGraphQLResponse(resp, true)
+}
+
+// parseGraphQLResponse parses the operations of introspection and suggestion
+// probe responses, the suggestions are probed if introspection is disabled.
+// Mutations are never requested.
+func parseGraphQLResponse(resp *navigation.Response, requestQueries bool) (navigationRequests []*navigation.Request) {
+	if !isJSONResponse(resp) || !strings.HasPrefix(strings.TrimSpace(resp.Body), "{") {
+		return
+	}
+	endpoint := resp.Resp.Request.URL.String()
+
+	var operations []utils.GraphQLOperation
+	if schema, err := utils.ParseGraphQLSchema(resp.Body); err == nil {
+		operations = schema.Operations()
+	} else if utils.IsGraphQLIntrospectionDisabled(resp.Body) {
+		for _, probe := range utils.GraphQLSuggestionProbes() {
+			navigationRequests = append(navigationRequests, newGraphQLRequest(endpoint, endpoint, probe, "suggestion", resp))
+		}
+		return
+	} else {
+		operations = utils.ParseGraphQLSuggestions(resp.Body)
+	}
+
+	for _, operation := range operations {
+		request := newGraphQLRequest(endpoint, endpoint, operation.Query, operation.Type, resp)
+		request.CustomFields = map[string][]string{"graphql_operation": {operation.Name}}
+		if len(operation.Arguments) > 0 {
+			request.CustomFields["graphql_arguments"] = operation.Arguments
+		}
+		request.SkipRequest = !requestQueries || operation.Type != "query"
+		navigationRequests = append(navigationRequests, request)
+	}
+	return
+}
+
+// newGraphQLRequest returns a json graphql post request of a query document
+func newGraphQLRequest(endpoint, source, query, attribute string, resp *navigation.Response) *navigation.Request {
+	request := navigation.NewNavigationRequestURLFromResponse(endpoint, source, "graphql", attribute, resp)
+	request.Method = http.MethodPost
+	request.Body = utils.GraphQLRequestBody(query)
+	request.Headers = map[string]string{"Content-Type": "application/json"}
+	return request
+}
+
+// isGraphQLRequest returns true if a captured request is a graphql request
+func isGraphQLRequest(request navigation.Request) bool {
+	for name, value := range request.Headers {
+		if strings.EqualFold(name, "Content-Type") && strings.Contains(value, "application/graphql") {
+			return true
+		}
+	}
+	if strings.Contains(request.URL, "query=") && graphQLPathRegex.MatchString(request.URL) {
+		return true
+	}
+	if !strings.Contains(request.Body, `"query"`) {
+		return false
+	}
+	var body map[string]interface{}
+	if err := jsoniter.UnmarshalFromString(request.Body, &body); err != nil {
+		return false
+	}
+	query, _ := body["query"].(string)
+	return graphQLDocumentRegex.MatchString(query)
+}
+
+// graphQLEndpoint returns the url of a graphql endpoint without its query
+func graphQLEndpoint(value string) string {
+	if index := strings.IndexAny(value, "?#"); index != -1 {
+		value = value[:index]
+	}
+	return value
+}
@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/stretchr/testify/require"
+)
+
+// graphQLTestSchema is the introspection result of the test graphql server
+const graphQLTestSchema = `{"data": {"__schema": {
+	"queryType": {"name": "Query"},
+	"mutationType": {"name": "Mutation"},
+	"types": [
+		{"kind": "OBJECT", "name": "Query", "fields": [
+			{"name": "users", "args": [{"name": "limit", "type": {"kind": "SCALAR", "name": "Int"}, "defaultValue": null}], "type": {"kind": "LIST", "ofType": {"kind": "OBJECT", "name": "User"}}},
+			{"name": "user", "args": [{"name": "id", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}, "defaultValue": null}], "type": {"kind": "OBJECT", "name": "User"}}
+		]},
+		{"kind": "OBJECT", "name": "Mutation", "fields": [
+			{"name": "createUser", "args": [{"name": "input", "type": {"kind": "NON_NULL", "ofType": {"kind": "INPUT_OBJECT", "name": "UserInput"}}, "defaultValue": null}], "type": {"kind": "OBJECT", "name": "User"}}
+		]},
+		{"kind": "OBJECT", "name": "User", "fields": [
+			{"name": "id", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}},
+			{"name": "name", "args": [], "type": {"kind": "SCALAR", "name": "String"}},
+			{"name": "posts", "args": [], "type": {"kind": "LIST", "ofType": {"kind": "OBJECT", "name": "Post"}}}
+		]},
+		{"kind": "INPUT_OBJECT", "name": "UserInput", "inputFields": [
+			{"name": "name", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "String"}}, "defaultValue": null},
+			{"name": "role", "type": {"kind": "NON_NULL", "ofType": {"kind": "ENUM", "name": "Role"}}, "defaultValue": null}
+		]},
+		{"kind": "ENUM", "name": "Role", "enumValues": [{"name": "ADMIN"}, {"name": "MEMBER"}]}
+	]
+}}}`
+
+// newGraphQLTestServer returns a graphql server which answers introspection,
+// suggestion probes and queries, and records the operations it executes.
+func newGraphQLTestServer(introspection bool, executed *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query string `json:"query"`
+		}
+		if r.URL.Path != "/graphql" || r.Method != http.MethodPost || jsoniter.NewDecoder(r.Body).Decode(&request) != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case strings.Contains(request.Query, "__schema") && introspection:
+			_, _ = w.Write([]byte(graphQLTestSchema))
+		case strings.Contains(request.Query, "__schema"):
+			_, _ = w.Write([]byte(`{"errors": [{"message": "GraphQL introspection is not allowed by Apollo Server"}]}`))
+		case strings.Contains(request.Query, "katanaQueryProbe"):
+			_, _ = w.Write([]byte(`{"errors": [
+				{"message": "Cannot query field \"katanaQueryProbe\" on type \"Query\"."},
+				{"message": "Cannot query field \"usr\" on type \"Query\". Did you mean \"user\" or \"users\"?"},
+				{"message": "Cannot query field \"usrs\" on type \"Query\". Did you mean \"users\" or \"user\"?"}
+			]}`))
+		case strings.Contains(request.Query, "katanaMutationProbe"):
+			_, _ = w.Write([]byte(`{"errors": [
+				{"message": "Cannot query field \"katanaMutationProbe\" on type \"Mutation\"."},
+				{"message": "Cannot query field \"creat\" on type \"Mutation\". Did you mean \"createUser\"?"}
+			]}`))
+		default:
+			*executed = append(*executed, request.Query)
+			_, _ = w.Write([]byte(`{"data": {}}`))
+		}
+	}))
+}
+
+// doGraphQLRequest performs a navigation request against the test server
+func doGraphQLRequest(t *testing.T, request *navigation.Request) *navigation.Response {
+	httpReq, err := http.NewRequest(request.Method, request.URL, strings.NewReader(request.Body))
+	require.NoError(t, err)
+	for name, value := range request.Headers {
+		httpReq.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return &navigation.Response{Resp: resp, Body: string(body), Depth: request.Depth}
+}
+
+func TestGraphQLParsers(t *testing.T) {
+	t.Run("endpoint detection", func(t *testing.T) {
+		script := `const client = new ApolloClient({uri: "/api/graphql", cache: new InMemoryCache()}); fetch('https://api.example.com/v1/gql?op=me');`
+		resp := newTestResponse("https://example.com/static/app.js", "application/javascript", script)
+		resp.XhrRequests = []navigation.Request{
+			{Method: http.MethodPost, URL: "https://example.com/internal/query", Body: `{"operationName":"Me","query":"query Me { me { id } }"}`},
+			{Method: http.MethodPost, URL: "https://example.com/api/users", Body: `{"query":"john"}`},
+		}
+
+		var endpoints []string
+		for _, request := range graphQLEndpointParser(resp) {
+			require.Equal(t, http.MethodPost, request.Method)
+			require.Contains(t, request.Body, "__schema")
+			endpoints = append(endpoints, request.URL)
+		}
+		require.Equal(t, []string{"https://example.com/api/graphql", "https://api.example.com/v1/gql", "https://example.com/internal/query"}, endpoints)
+
+		require.Len(t, graphQLEndpointParser(newTestResponse("https://example.com/graphql?query={__typename}", "application/json", `{"data":{"__typename":"Query"}}`)), 1)
+	})
+
+	t.Run("introspection", func(t *testing.T) {
+		var executed []string
+		server := newGraphQLTestServer(true, &executed)
+		defer server.Close()
+
+		introspection := graphQLEndpointParser(newTestResponse(server.URL+"/", "text/html", `<script>fetch("/graphql")</script>`))
+		require.Len(t, introspection, 1)
+
+		operations := graphQLOperationsParser(doGraphQLRequest(t, introspection[0]))
+		require.Len(t, operations, 3)
+		for _, operation := range operations {
+			require.True(t, operation.SkipRequest)
+			require.Equal(t, server.URL+"/graphql", operation.URL)
+		}
+		require.Equal(t, "query", operations[0].Attribute)
+		require.Equal(t, []string{"user"}, operations[0].CustomFields["graphql_operation"])
+		require.Equal(t, []string{"id: ID!"}, operations[0].CustomFields["graphql_arguments"])
+		require.Equal(t, `{"query":"query { user(id: \"1\") { id name } }"}`, operations[0].Body)
+		require.Equal(t, `{"query":"query { users { id name } }"}`, operations[1].Body)
+		require.Equal(t, "mutation", operations[2].Attribute)
+		require.Equal(t, []string{"input: UserInput!"}, operations[2].CustomFields["graphql_arguments"])
+		require.Equal(t, `{"query":"mutation { createUser(input: {name: \"katana\", role: ADMIN}) { id name } }"}`, operations[2].Body)
+
+		// only the queries are requested, mutations are output without being executed
+		for _, operation := range graphQLQueriesParser(doGraphQLRequest(t, introspection[0])) {
+			require.Equal(t, operation.Attribute == "mutation", operation.SkipRequest)
+			if !operation.SkipRequest {
+				doGraphQLRequest(t, operation)
+			}
+		}
+		require.Equal(t, []string{`query { user(id: "1") { id name } }`, `query { users { id name } }`}, executed)
+	})
+
+	t.Run("field suggestions", func(t *testing.T) {
+		var executed []string
+		server := newGraphQLTestServer(false, &executed)
+		defer server.Close()
+
+		introspection := graphQLEndpointParser(newTestResponse(server.URL+"/graphql", "text/html", `<title>GraphQL Playground</title>`))
+		require.Len(t, introspection, 1)
+
+		probes := graphQLOperationsParser(doGraphQLRequest(t, introspection[0]))
+		require.Len(t, probes, 2)
+
+		var operations []string
+		for _, probe := range probes {
+			require.Equal(t, "suggestion", probe.Attribute)
+			require.False(t, probe.SkipRequest)
+			for _, operation := range graphQLOperationsParser(doGraphQLRequest(t, probe)) {
+				require.True(t, operation.SkipRequest)
+				operations = append(operations, operation.Attribute+" "+operation.CustomFields["graphql_operation"][0])
+			}
+		}
+		require.Equal(t, []string{"query user", "query users", "mutation createUser"}, operations)
+		require.Empty(t, executed)
+	})
+}
@@ -0,0 +1,336 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+	"golang.org/x/net/html"
+)
+
+var (
+	// inlineStateRegex matches the assignments of server side rendered state,
+	// e.g. window.__INITIAL_STATE__ = {...} or window.__NUXT__ = ...
+	inlineStateRegex = regexp.MustCompile(`(?:window|self|globalThis)\s*\.\s*(__[A-Za-z0-9_]+__)\s*=\s*`)
+	// inlinePathRegex matches the root relative paths of free text
+	inlinePathRegex = regexp.MustCompile(`(?:^|[\s(\[=,:])(/[A-Za-z0-9_\-.~%][A-Za-z0-9_\-.~%/]*(?:\?[A-Za-z0-9_\-.~%&=+]*)?)`)
+	// nextDynamicSegmentRegex matches the dynamic segments of next.js routes, e.g. [id] or [...slug]
+	nextDynamicSegmentRegex = regexp.MustCompile(`\[\[?(?:\.\.\.)?([^\]]+)\]\]?`)
+)
+
+// inlineURLAttributes are the attributes whose value is a link even if relative
+var inlineURLAttributes = map[string]struct{}{
+	"href": {}, "src": {}, "action": {}, "formaction": {}, "poster": {}, "data": {}, "cite": {}, "background": {}, "manifest": {},
+}
+
+// inlineDataParser mines the html comments, hidden input values, template and
+// noscript contents, and the inline json and state blobs of pages, results
+// are tagged with the place they come from.
+func inlineDataParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
+	miner := &inlineDataMiner{resp: resp, source: resp.Resp.Request.URL.String()}
+	for _, node := range resp.Reader.Nodes {
+		miner.comments(node)
+	}
+	resp.Reader.Find("input").Each(func(i int, item *goquery.Selection) {
+		if inputType, _ := item.Attr("type"); !strings.EqualFold(strings.TrimSpace(inputType), "hidden") {
+			return
+		}
+		value, _ := item.Attr("value")
+		for _, value := range utils.ExtractURLLikeValues(value) {
+			miner.add(value, "input", "hidden")
+		}
+	})
+	resp.Reader.Find("template").Each(func(i int, item *goquery.Selection) {
+		if content, err := item.Html(); err == nil {
+			miner.fragment(content, "template")
+		}
+	})
+	// noscript contents are raw text for parsers with scripting enabled
+	resp.Reader.Find("noscript").Each(func(i int, item *goquery.Selection) {
+		miner.fragment(item.Text(), "noscript")
+	})
+	resp.Reader.Find("script").Each(func(i int, item *goquery.Selection) {
+		if _, ok := item.Attr("src"); ok {
+			return
+		}
+		scriptType, _ := item.Attr("type")
+		scriptType = strings.ToLower(strings.TrimSpace(scriptType))
+		id, _ := item.Attr("id")
+
+		switch {
+		case scriptType == "application/json" || strings.HasSuffix(scriptType, "+json"):
+			name := id
+			if name == "" {
+				name = strings.TrimPrefix(scriptType, "application/")
+			}
+			miner.jsonBlob(item.Text(), name)
+		case scriptType == "" || scriptType == "module" || strings.Contains(scriptType, "javascript"):
+			miner.stateBlobs(item.Text())
+		}
+	})
+	return miner.requests
+}
+
+type inlineDataMiner struct {
+	resp     *navigation.Response
+	source   string
+	requests []*navigation.Request
+}
+
+// add adds a request for a value resolved against the response url
+func (m *inlineDataMiner) add(value, tag, attribute string) {
+	m.requests = append(m.requests, navigation.NewNavigationRequestURLFromResponse(value, m.source, tag, attribute, m.resp))
+}
+
+// comments mines the html comments of the node tree, commented out markup
+// included.
+func (m *inlineDataMiner) comments(node *html.Node) {
+	if node.Type == html.CommentNode {
+		m.fragment(node.Data, "comment")
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		m.comments(child)
+	}
+}
+
+// fragment mines the link attributes, url-like attribute values, framework
+// bindings and text of an html fragment.
+func (m *inlineDataMiner) fragment(content, tag string) {
+	if strings.TrimSpace(content) == "" {
+		return
+	}
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return
+	}
+	document.Find("*").Each(func(i int, item *goquery.Selection) {
+		for _, attribute := range item.Nodes[0].Attr {
+			name := strings.ToLower(attribute.Key)
+			value := strings.TrimSpace(attribute.Val)
+			if value == "" {
+				continue
+			}
+			if _, ok := inlineURLAttributes[name]; ok {
+				m.add(value, tag, name)
+				continue
+			}
+			// bindings are javascript expressions, e.g. :href="'/users/' + id"
+			if strings.HasPrefix(name, ":") || strings.HasPrefix(name, "v-bind:") || strings.HasPrefix(name, "x-bind:") {
+				for _, endpoint := range utils.ExtractRelativeEndpoints(value) {
+					m.add(endpoint, tag, name)
+				}
+				continue
+			}
+			for _, value := range utils.ExtractURLLikeValues(value) {
+				m.add(value, tag, name)
+			}
+		}
+	})
+	m.text(document.Text(), tag)
+}
+
+// text mines the absolute urls and root relative paths of free text
+func (m *inlineDataMiner) text(content, tag string) {
+	values := utils.ExtractURLLikeValues(content)
+	for _, match := range inlinePathRegex.FindAllStringSubmatch(content, -1) {
+		if path := strings.TrimRight(match[1], ".,;:"); utils.IsURLLike(path) {
+			values = append(values, path)
+		}
+	}
+	for _, value := range values {
+		m.add(value, tag, "text")
+	}
+}
+
+// jsonBlob mines an inline json document, e.g. __NEXT_DATA__ or ld+json
+func (m *inlineDataMiner) jsonBlob(content, name string) {
+	var document interface{}
+	if err := jsoniter.UnmarshalFromString(strings.TrimSpace(content), &document); err != nil {
+		return
+	}
+	if name == "__NEXT_DATA__" {
+		m.nextData(document)
+	}
+	m.walk(document, name)
+}
+
+// stateBlobs mines the state objects assigned by inline scripts, which are
+// either json, parsed json strings or javascript expressions.
+func (m *inlineDataMiner) stateBlobs(script string) {
+	for _, match := range inlineStateRegex.FindAllStringSubmatchIndex(script, -1) {
+		name := script[match[2]:match[3]]
+		value := script[match[1]:]
+		if data, ok := inlineJSONValue(value); ok {
+			var document interface{}
+			if err := jsoniter.UnmarshalFromString(data, &document); err == nil {
+				m.walk(document, name)
+				continue
+			}
+			value = data
+		}
+		// javascript object literals and functions are matched for endpoints
+		for _, endpoint := range utils.ExtractRelativeEndpoints(value) {
+			m.add(endpoint, "script", name)
+		}
+	}
+}
+
+// walk adds the links and url-like values of a json document
+func (m *inlineDataMiner) walk(document interface{}, name string) {
+	extractor := &jsonLinkExtractor{resp: m.resp, source: m.source, tag: "script", attribute: name, seen: make(map[string]struct{})}
+	extractor.walk(document)
+	m.requests = append(m.requests, extractor.requests...)
+}
+
+// nextData adds the build manifest holding the route map of a next.js site
+// and the data url of the page, whose dynamic segments are filled from the
+// page query. Unresolved page routes are dropped from the document.
+func (m *inlineDataMiner) nextData(document interface{}) {
+	data, ok := document.(map[string]interface{})
+	if !ok {
+		return
+	}
+	buildID, _ := data["buildId"].(string)
+	page, _ := data["page"].(string)
+	if buildID == "" || strings.ContainsAny(buildID, "/?#") {
+		return
+	}
+	m.add("/_next/static/"+buildID+"/_buildManifest.js", "script", "__NEXT_DATA__")
+	if page == "" {
+		return
+	}
+
+	query, _ := data["query"].(map[string]interface{})
+	resolved := true
+	route := nextDynamicSegmentRegex.ReplaceAllStringFunc(page, func(segment string) string {
+		name := nextDynamicSegmentRegex.FindStringSubmatch(segment)[1]
+		switch value := query[name].(type) {
+		case string:
+			return value
+		case []interface{}:
+			var parts []string
+			for _, part := range value {
+				if text, ok := part.(string); ok {
+					parts = append(parts, text)
+				}
+			}
+			return strings.Join(parts, "/")
+		}
+		resolved = false
+		return segment
+	})
+	if !resolved {
+		delete(data, "page")
+		return
+	}
+	data["page"] = route
+	if route == "/" {
+		route = "/index"
+	}
+	m.add("/_next/data/"+buildID+route+".json", "script", "__NEXT_DATA__")
+}
+
+// inlineJSONValue returns the json object or array at the start of a
+// javascript expression, or the string parsed by a JSON.parse call.
+func inlineJSONValue(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "JSON.parse(") {
+		literal, ok := scanJSValue(strings.TrimSpace(value[len("JSON.parse("):]))
+		if !ok || literal[0] == '{' || literal[0] == '[' {
+			return "", false
+		}
+		return unescapeJSString(literal[1 : len(literal)-1]), true
+	}
+	if !strings.HasPrefix(value, "{") && !strings.HasPrefix(value, "[") {
+		return "", false
+	}
+	return scanJSValue(value)
+}
+
+// scanJSValue returns the balanced object, array or string literal at the
+// start of a javascript expression.
+func scanJSValue(value string) (string, bool) {
+	if value == "" {
+		return "", false
+	}
+	var depth int
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		char := value[i]
+		if quote != 0 {
+			switch char {
+			case '\\':
+				i++
+			case quote:
+				quote = 0
+				if depth == 0 {
+					return value[:i+1], true
+				}
+			}
+			continue
+		}
+		switch char {
+		case '"', '\'', '`':
+			quote = char
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return value[:i+1], true
+			}
+		default:
+			if depth == 0 {
+				return "", false
+			}
+		}
+	}
+	return "", false
+}
+
+// unescapeJSString returns the value of the content of a javascript string literal
+func unescapeJSString(value string) string {
+	if !strings.Contains(value, "\\") {
+		return value
+	}
+	builder := &strings.Builder{}
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			builder.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch char := value[i]; char {
+		case 'n':
+			builder.WriteByte('\n')
+		case 't':
+			builder.WriteByte('\t')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'b':
+			builder.WriteByte('\b')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'u', 'x':
+			size := 4
+			if char == 'x' {
+				size = 2
+			}
+			if i+size < len(value) {
+				if code, err := strconv.ParseUint(value[i+1:i+1+size], 16, 32); err == nil {
+					builder.WriteRune(rune(code))
+					i += size
+					continue
+				}
+			}
+			builder.WriteByte(char)
+		default:
+			builder.WriteByte(char)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	urlutil "github.com/projectdiscovery/utils/url"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInlineDataParser(t *testing.T) {
+	parse := func(body string) []string {
+		parsed, _ := urlutil.Parse("https://example.com/products/42")
+		document, _ := goquery.NewDocumentFromReader(strings.NewReader(body))
+		resp := &navigation.Response{Resp: &http.Response{Request: &http.Request{URL: parsed.URL}}, Reader: document, Body: body}
+
+		var results []string
+		for _, request := range inlineDataParser(resp) {
+			results = append(results, request.Tag+" "+request.Attribute+" "+request.URL)
+		}
+		return results
+	}
+
+	t.Run("markup", func(t *testing.T) {
+		results := parse(`<html><body>
+<!-- TODO: remove the legacy endpoint /api/v1/legacy-export before release -->
+<!-- <a href="old-admin/">admin</a> -->
+<form><input type="hidden" name="next" value="/checkout/confirm"><input type="HIDDEN" name="ctx" value='{"cdn":"https://cdn.example.com/assets/"}'><input name="q" value="/search"></form>
+<template id="row"><tr><td><a :href="'/users/' + id + '/edit'">edit</a></td></tr></template>
+<noscript><img src="/pixel.gif?noscript=1"></noscript>
+</body></html>`)
+		require.Equal(t, []string{
+			"comment text https://example.com/api/v1/legacy-export",
+			"comment href https://example.com/products/old-admin/",
+			"input hidden https://example.com/checkout/confirm",
+			"input hidden https://cdn.example.com/assets/",
+			"template :href https://example.com/users/",
+			"noscript src https://example.com/pixel.gif?noscript=1",
+		}, results)
+	})
+
+	t.Run("data blobs", func(t *testing.T) {
+		results := parse(`<html><head>
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "Organization", "url": "/about", "logo": "https://example.com/logo.png"}</script>
+<script id="__NEXT_DATA__" type="application/json">{"props": {"pageProps": {"apiBase": "/api/v2/products"}}, "page": "/products/[id]", "query": {"id": "42"}, "buildId": "b1d"}</script>
+<script>window.__INITIAL_STATE__ = JSON.parse("{\"session\":{\"refresh\":\"\\/api\\/session\\/refresh\"}}"); window.__APOLLO_STATE__ = {"ROOT_QUERY": {"me": null}};</script>
+<script>window.__NUXT__=(function(a){return {config:{app:{cdnURL:"/_nuxt/"}},routePath:"/products/shoes"}}(null));</script>
+</head></html>`)
+		require.Equal(t, []string{
+			"script ld+json https://schema.org",
+			"script ld+json https://example.com/logo.png",
+			"script ld+json https://example.com/about",
+			"script __NEXT_DATA__ https://example.com/_next/static/b1d/_buildManifest.js",
+			"script __NEXT_DATA__ https://example.com/_next/data/b1d/products/42.json",
+			"script __NEXT_DATA__ https://example.com/products/42",
+			"script __NEXT_DATA__ https://example.com/api/v2/products",
+			"script __INITIAL_STATE__ https://example.com/api/session/refresh",
+			"script __NUXT__ https://example.com/_nuxt/",
+			"script __NUXT__ https://example.com/products/shoes",
+		}, results)
+	})
+
+	t.Run("state actions", func(t *testing.T) {
+		body := `<script>window.__REDUX_STATE__ = {"order": {"actions": [{"name": "delete-order", "method": "DELETE", "href": "/api/orders/42"}, {"name": "track", "href": "/api/orders/42/tracking"}]}};</script>`
+		parsed, _ := urlutil.Parse("https://example.com/orders/42")
+		document, _ := goquery.NewDocumentFromReader(strings.NewReader(body))
+		resp := &navigation.Response{Resp: &http.Response{Request: &http.Request{URL: parsed.URL}}, Reader: document, Body: body}
+
+		skipped := make(map[string]bool)
+		for _, request := range inlineDataParser(resp) {
+			skipped[request.Method+" "+request.URL] = request.SkipRequest
+		}
+		require.Equal(t, map[string]bool{
+			"DELETE https://example.com/api/orders/42":       true,
+			"GET https://example.com/api/orders/42/tracking": false,
+		}, skipped)
+	})
+}
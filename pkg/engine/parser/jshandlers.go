@@ -0,0 +1,389 @@
+package parser
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+)
+
+// jsTokenKind is the kind of a javascript token
+type jsTokenKind int
+
+const (
+	jsIdentifier jsTokenKind = iota + 1
+	jsString
+	jsNumber
+	jsPunctuator
+)
+
+// jsToken is a javascript token, string values are unquoted and unescaped
+type jsToken struct {
+	kind  jsTokenKind
+	value string
+}
+
+// jsPunctuators are the multi character punctuators, longest first
+var jsPunctuators = []string{"===", "!==", "==", "!=", "=>", "+=", "-=", "&&", "||", "??", "?.", "<=", ">="}
+
+// tokenizeJS splits a javascript snippet into tokens. It is not a complete
+// tokenizer, regular expression literals are tokenized as punctuators and
+// template literals are cut at their first substitution.
+func tokenizeJS(source string) []jsToken {
+	var tokens []jsToken
+	for i := 0; i < len(source); {
+		char := source[i]
+		switch {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r' || char == ';':
+			if char == ';' {
+				tokens = append(tokens, jsToken{kind: jsPunctuator, value: ";"})
+			}
+			i++
+		case strings.HasPrefix(source[i:], "//"):
+			end := strings.IndexByte(source[i:], '\n')
+			if end == -1 {
+				return tokens
+			}
+			i += end
+		case strings.HasPrefix(source[i:], "/*"):
+			end := strings.Index(source[i+2:], "*/")
+			if end == -1 {
+				return tokens
+			}
+			i += end + 4
+		case char == '"' || char == '\'' || char == '`':
+			value, next := readJSString(source, i)
+			tokens = append(tokens, jsToken{kind: jsString, value: value})
+			i = next
+		case isJSIdentifierChar(char) && !(char >= '0' && char <= '9'):
+			start := i
+			for i < len(source) && isJSIdentifierChar(source[i]) {
+				i++
+			}
+			tokens = append(tokens, jsToken{kind: jsIdentifier, value: source[start:i]})
+		case char >= '0' && char <= '9':
+			start := i
+			for i < len(source) && (isJSIdentifierChar(source[i]) || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, jsToken{kind: jsNumber, value: source[start:i]})
+		default:
+			value := source[i : i+1]
+			for _, punctuator := range jsPunctuators {
+				if strings.HasPrefix(source[i:], punctuator) {
+					value = punctuator
+					break
+				}
+			}
+			tokens = append(tokens, jsToken{kind: jsPunctuator, value: value})
+			i += len(value)
+		}
+	}
+	return tokens
+}
+
+// readJSString reads the string starting with a quote at start, returning
+// its unescaped value and the index following it.
+func readJSString(source string, start int) (string, int) {
+	quote := source[start]
+	var builder strings.Builder
+	substitution := false
+	i := start + 1
+	for ; i < len(source); i++ {
+		char := source[i]
+		if char == quote {
+			return builder.String(), i + 1
+		}
+		if char == '\\' && i+1 < len(source) {
+			i++
+			if !substitution {
+				builder.WriteByte(source[i])
+			}
+			continue
+		}
+		// the value of a template literal stops at its first substitution
+		if quote == '`' && strings.HasPrefix(source[i:], "${") {
+			substitution = true
+		}
+		if !substitution {
+			builder.WriteByte(char)
+		}
+	}
+	return builder.String(), i
+}
+
+func isJSIdentifierChar(char byte) bool {
+	return char == '_' || char == '$' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9')
+}
+
+// jsNavigation is a navigation found in a javascript snippet
+type jsNavigation struct {
+	method string
+	url    string
+}
+
+// jsHTTPMethods are the methods of request helpers such as $.post or axios.put
+var jsHTTPMethods = map[string]string{
+	"get": http.MethodGet, "getjson": http.MethodGet, "load": http.MethodGet, "post": http.MethodPost,
+	"put": http.MethodPut, "patch": http.MethodPatch, "delete": http.MethodDelete,
+}
+
+// extractJSNavigations returns the navigations of the snippet sinks: location
+// assignments and calls, window.open, form actions, fetch, xhr, jquery and
+// axios requests, or calls having a url-like string as first argument.
+func extractJSNavigations(source string) []jsNavigation {
+	tokens := tokenizeJS(source)
+
+	var navigations []jsNavigation
+	add := func(method, value string, explicit bool) {
+		value = strings.TrimSpace(value)
+		if value == "" || stringsutil.HasPrefixAnyI(value, "#", "javascript:", "data:", "mailto:", "tel:") {
+			return
+		}
+		// values of generic calls must look like urls to avoid junk
+		if !explicit && !utils.IsURLLike(value) {
+			return
+		}
+		if method == "" {
+			method = http.MethodGet
+		}
+		navigations = append(navigations, jsNavigation{method: method, url: value})
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		// member chains are only read from their first identifier
+		if tokens[i].kind != jsIdentifier || (i > 0 && isJSPunctuator(tokens[i-1], ".", "?.")) {
+			continue
+		}
+		chain, next := readJSMemberChain(tokens, i)
+		if next >= len(tokens) {
+			break
+		}
+		last := strings.ToLower(chain[len(chain)-1])
+		hasLocation := containsFold(chain, "location")
+
+		switch {
+		case isJSPunctuator(tokens[next], "="):
+			value, ok := readJSStringExpression(tokens, next+1)
+			if !ok {
+				continue
+			}
+			if (hasLocation && (last == "location" || last == "href")) || last == "action" || last == "formaction" {
+				add("", value, true)
+			}
+		case isJSPunctuator(tokens[next], "("):
+			arguments := readJSArguments(tokens, next+1)
+			if len(arguments) == 0 {
+				continue
+			}
+			first := arguments[0]
+			switch {
+			case last == "open" && len(arguments) > 1 && arguments[1].isString && isHTTPMethod(first.value):
+				// xhr.open(method, url)
+				add(strings.ToUpper(first.value), arguments[1].value, true)
+			case last == "open" || (hasLocation && (last == "assign" || last == "replace")):
+				add("", first.value, first.isString)
+			case last == "fetch":
+				method := ""
+				if len(arguments) > 1 {
+					method = strings.ToUpper(arguments[1].properties["method"])
+				}
+				add(method, first.value, first.isString)
+			case last == "ajax":
+				if first.isString {
+					method := ""
+					if len(arguments) > 1 {
+						method = jsObjectMethod(arguments[1].properties)
+					}
+					add(method, first.value, true)
+				} else if first.properties != nil {
+					add(jsObjectMethod(first.properties), first.properties["url"], true)
+				}
+			case len(chain) > 1 && jsHTTPMethods[last] != "":
+				// $.get, $.post, jQuery.getJSON, axios.put, $http.delete, etc.
+				if first.isString {
+					add(jsHTTPMethods[last], first.value, true)
+				} else if first.properties != nil {
+					add(jsHTTPMethods[last], first.properties["url"], true)
+				}
+			case first.isString:
+				add("", first.value, false)
+			}
+		}
+	}
+	return navigations
+}
+
+// readJSMemberChain reads identifiers separated by dots starting at index
+func readJSMemberChain(tokens []jsToken, index int) ([]string, int) {
+	chain := []string{tokens[index].value}
+	i := index + 1
+	for i+1 < len(tokens) && isJSPunctuator(tokens[i], ".", "?.") && tokens[i+1].kind == jsIdentifier {
+		chain = append(chain, tokens[i+1].value)
+		i += 2
+	}
+	return chain, i
+}
+
+// readJSStringExpression reads the concatenation of string literals starting at
+// index. Concatenations with non literals are cut, keeping the literal prefix.
+func readJSStringExpression(tokens []jsToken, index int) (string, bool) {
+	if index >= len(tokens) || tokens[index].kind != jsString {
+		return "", false
+	}
+	var builder strings.Builder
+	builder.WriteString(tokens[index].value)
+	for i := index + 1; i+1 < len(tokens) && isJSPunctuator(tokens[i], "+") && tokens[i+1].kind == jsString; i += 2 {
+		builder.WriteString(tokens[i+1].value)
+	}
+	return builder.String(), true
+}
+
+// jsArgument is a call argument, either a string expression or an object
+// literal with its string properties.
+type jsArgument struct {
+	value      string
+	isString   bool
+	properties map[string]string
+}
+
+// readJSArguments reads the arguments of a call starting after its parenthesis.
+// Reading stops at the closing parenthesis or at an unbalanced closing brace
+// or bracket.
+func readJSArguments(tokens []jsToken, index int) []jsArgument {
+	var arguments []jsArgument
+	for i := index; i < len(tokens); {
+		if isJSPunctuator(tokens[i], ")") {
+			break
+		}
+		var argument jsArgument
+		switch {
+		case tokens[i].kind == jsString:
+			argument.value, argument.isString = readJSStringExpression(tokens, i)
+		case isJSPunctuator(tokens[i], "{"):
+			argument.properties = readJSObject(tokens, i+1)
+		}
+		arguments = append(arguments, argument)
+
+		// skip to the next argument of the call
+		depth := 0
+		for ; i < len(tokens); i++ {
+			if isJSPunctuator(tokens[i], "(", "{", "[") {
+				depth++
+			} else if isJSPunctuator(tokens[i], ")", "}", "]") {
+				if depth == 0 && isJSPunctuator(tokens[i], ")") {
+					break
+				}
+				if depth == 0 {
+					return arguments
+				}
+				depth--
+			} else if depth == 0 && isJSPunctuator(tokens[i], ",") {
+				i++
+				break
+			}
+		}
+		if i < len(tokens) && isJSPunctuator(tokens[i], ")") {
+			break
+		}
+	}
+	return arguments
+}
+
+// readJSObject reads the string properties of an object literal starting after its brace
+func readJSObject(tokens []jsToken, index int) map[string]string {
+	properties := make(map[string]string)
+	depth := 0
+	for i := index; i+2 < len(tokens); i++ {
+		switch {
+		case isJSPunctuator(tokens[i], "(", "{", "["):
+			depth++
+		case isJSPunctuator(tokens[i], ")", "}", "]"):
+			if depth == 0 {
+				return properties
+			}
+			depth--
+		case depth == 0 && (tokens[i].kind == jsIdentifier || tokens[i].kind == jsString) && isJSPunctuator(tokens[i+1], ":"):
+			if value, ok := readJSStringExpression(tokens, i+2); ok {
+				properties[strings.ToLower(tokens[i].value)] = value
+			}
+		}
+	}
+	return properties
+}
+
+// jsObjectMethod returns the method of jquery ajax settings
+func jsObjectMethod(properties map[string]string) string {
+	if method := properties["method"]; method != "" {
+		return strings.ToUpper(method)
+	}
+	return strings.ToUpper(properties["type"])
+}
+
+// isHTTPMethod returns true if the value is an http method
+func isHTTPMethod(value string) bool {
+	switch strings.ToUpper(value) {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func isJSPunctuator(token jsToken, values ...string) bool {
+	if token.kind != jsPunctuator {
+		return false
+	}
+	for _, value := range values {
+		if token.value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, item := range values {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyJSHandlersParser parses navigations from inline event handlers
+// and javascript: urls, tagged with the attribute they were found in.
+func bodyJSHandlersParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
+	resp.Reader.Find("*").Each(func(i int, item *goquery.Selection) {
+		tag := goquery.NodeName(item)
+		for _, attribute := range item.Nodes[0].Attr {
+			name := strings.ToLower(attribute.Key)
+			value := strings.TrimSpace(attribute.Val)
+
+			var source string
+			switch {
+			case strings.HasPrefix(name, "on") && value != "":
+				source = value
+			case stringsutil.HasPrefixI(value, "javascript:"):
+				source = value[len("javascript:"):]
+				if unescaped, err := url.PathUnescape(source); err == nil {
+					source = unescaped
+				}
+			default:
+				continue
+			}
+
+			for _, jsItem := range extractJSNavigations(source) {
+				request := navigation.NewNavigationRequestURLFromResponse(jsItem.url, resp.Resp.Request.URL.String(), tag, name, resp)
+				request.Method = jsItem.method
+				// requests which could change the state of the target are output only
+				request.SkipRequest = !navigation.IsSafeMethod(jsItem.method)
+				navigationRequests = append(navigationRequests, request)
+			}
+		}
+	})
+	return
+}
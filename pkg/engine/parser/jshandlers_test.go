@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	urlutil "github.com/projectdiscovery/utils/url"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenizeJS(t *testing.T) {
+	// non ascii characters outside strings are tokenized byte by byte
+	tokens := tokenizeJS("a = €'/x'; b(é\"/y\")")
+	var values []string
+	for _, token := range tokens {
+		if token.kind == jsString {
+			values = append(values, token.value)
+		}
+	}
+	require.Equal(t, []string{"/x", "/y"}, values)
+}
+
+func TestExtractJSNavigations(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected []jsNavigation
+	}{
+		{`location.href='/x'`, []jsNavigation{{"GET", "/x"}}},
+		{`window.location = "/users/" + id; return false;`, []jsNavigation{{"GET", "/users/"}}},
+		{`document.location.assign('/assign')`, []jsNavigation{{"GET", "/assign"}}},
+		{`window.open('/y', '_blank')`, []jsNavigation{{"GET", "/y"}}},
+		{`navigate('/z')`, []jsNavigation{{"GET", "/z"}}},
+		{`alert('hello'); toggle("menu")`, nil},
+		{`fetch("/api/items", {method: 'POST', body: JSON.stringify({a: 1})})`, []jsNavigation{{"POST", "/api/items"}}},
+		{`$.ajax({url: "/api/save", type: "put", data: {id: 1}})`, []jsNavigation{{"PUT", "/api/save"}}},
+		{`$.post('/api/vote?id=' + this.id)`, []jsNavigation{{"POST", "/api/vote?id="}}},
+		{`var x = new XMLHttpRequest(); x.open("DELETE", "/api/item/1"); x.send()`, []jsNavigation{{"DELETE", "/api/item/1"}}},
+		{"this.form.action = `/submit/${step}`; this.form.submit()", []jsNavigation{{"GET", "/submit/"}}},
+		{`/* location.href='/comment' */ location.replace("#top")`, nil},
+		{`f('/x']`, []jsNavigation{{"GET", "/x"}}},
+		{`f('/x'}; location.href = '/y'`, []jsNavigation{{"GET", "/x"}, {"GET", "/y"}}},
+		{`$.ajax({url: '/api/a', type: 'get'}]`, []jsNavigation{{"GET", "/api/a"}}},
+		{`f(]]]`, nil},
+		{`f(`, nil},
+		{`x = y €'/a'; location.href = '/b'`, []jsNavigation{{"GET", "/b"}}},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, extractJSNavigations(test.source), "invalid navigations for %s", test.source)
+	}
+}
+
+func TestBodyJSHandlersParser(t *testing.T) {
+	parsed, _ := urlutil.Parse("https://security-crawl-maze.app/javascript/")
+	documentReader, _ := goquery.NewDocumentFromReader(strings.NewReader(`<button onclick="location.href='/test/onclick.found'">go</button>
+<a href="javascript:navigate('/test/javascript-url.found')">link</a>
+<form><button formaction="javascript:$.post(&quot;/test/formaction.found&quot;)">save</button></form>
+<a href="/regular">regular</a>`))
+	resp := &navigation.Response{Resp: &http.Response{Request: &http.Request{URL: parsed.URL}}, Reader: documentReader}
+	navigationRequests := bodyJSHandlersParser(resp)
+	require.Len(t, navigationRequests, 3)
+
+	require.Equal(t, "https://security-crawl-maze.app/test/onclick.found", navigationRequests[0].URL)
+	require.Equal(t, "button", navigationRequests[0].Tag)
+	require.Equal(t, "onclick", navigationRequests[0].Attribute)
+	require.Equal(t, "https://security-crawl-maze.app/test/javascript-url.found", navigationRequests[1].URL)
+	require.Equal(t, "href", navigationRequests[1].Attribute)
+	require.Equal(t, "https://security-crawl-maze.app/test/formaction.found", navigationRequests[2].URL)
+	require.Equal(t, http.MethodPost, navigationRequests[2].Method)
+	require.Equal(t, "formaction", navigationRequests[2].Attribute)
+
+	// requests which could change the state of the target are not performed
+	require.False(t, navigationRequests[0].SkipRequest)
+	require.True(t, navigationRequests[2].SkipRequest)
+}
@@ -0,0 +1,248 @@
+package parser
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+)
+
+// jsonLinkKeys are the keys whose string value is a link even if relative
+var jsonLinkKeys = map[string]struct{}{
+	"href": {}, "url": {}, "uri": {}, "link": {},
+}
+
+// jsonODataKeys are the OData annotations holding links
+var jsonODataKeys = map[string]struct{}{
+	"@odata.nextLink": {}, "@odata.deltaLink": {}, "@odata.id": {}, "@odata.editLink": {}, "@odata.readLink": {},
+	"@odata.navigationLink": {}, "@odata.associationLink": {}, "@odata.mediaReadLink": {}, "@odata.mediaEditLink": {},
+	"odata.nextLink": {}, "odata.deltaLink": {}, "odata.id": {}, "odata.editLink": {}, "odata.readLink": {},
+}
+
+// jsonContentParser parses the links of json responses: HAL _links, JSON:API
+// links, Siren links and actions, OData annotations and url-like values.
+func jsonContentParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
+	if !isJSONResponse(resp) {
+		return
+	}
+	var document interface{}
+	if err := jsoniter.UnmarshalFromString(resp.Body, &document); err != nil {
+		return
+	}
+	extractor := &jsonLinkExtractor{resp: resp, source: resp.Resp.Request.URL.String(), tag: "json", seen: make(map[string]struct{})}
+	extractor.walk(document)
+	return extractor.requests
+}
+
+// isJSONResponse returns true if the response has a json or +json content type
+func isJSONResponse(resp *navigation.Response) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Resp.Header.Get("Content-Type"))
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		return true
+	}
+	return mediaType == "" && strings.HasSuffix(strings.ToLower(resp.Resp.Request.URL.Path), ".json")
+}
+
+type jsonLinkExtractor struct {
+	resp   *navigation.Response
+	source string
+	tag    string
+	// attribute overrides the attribute of the links if set, e.g. with
+	// the name of the inline data blob the document comes from.
+	attribute string
+	seen      map[string]struct{}
+	requests  []*navigation.Request
+}
+
+// walk walks the json value looking for hypermedia links and url-like values
+func (e *jsonLinkExtractor) walk(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedJSONKeys(v) {
+			child := v[key]
+			if _, ok := jsonODataKeys[key]; ok {
+				if link, ok := child.(string); ok {
+					e.add(link, "odata")
+				}
+				continue
+			}
+			switch key {
+			case "_links":
+				e.links(child, "hal")
+			case "links":
+				e.links(child, "links")
+			case "actions":
+				if !e.sirenActions(child) {
+					e.walk(child)
+				}
+			default:
+				if text, ok := child.(string); ok {
+					if _, ok := jsonLinkKeys[strings.ToLower(key)]; ok {
+						e.add(text, key)
+					} else if utils.IsURLLike(text) {
+						e.add(text, "value")
+					}
+					continue
+				}
+				e.walk(child)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			e.walk(item)
+		}
+	}
+}
+
+// links extracts HAL and JSON:API links objects keyed by relation, whose
+// values are urls or link objects, and Siren links arrays.
+func (e *jsonLinkExtractor) links(value interface{}, attribute string) {
+	switch v := value.(type) {
+	case string:
+		e.add(v, attribute)
+	case []interface{}:
+		for _, item := range v {
+			e.links(item, attribute)
+		}
+	case map[string]interface{}:
+		// link objects have an href, link collections are keyed by relation
+		if href, ok := v["href"].(string); ok {
+			e.add(href, attribute)
+			return
+		}
+		for _, key := range sortedJSONKeys(v) {
+			e.links(v[key], attribute)
+		}
+	}
+}
+
+// sirenActions extracts Siren actions with their method and fields. It returns
+// false if the value is not an array of actions.
+func (e *jsonLinkExtractor) sirenActions(value interface{}) bool {
+	actions, ok := value.([]interface{})
+	if !ok {
+		return false
+	}
+	found := false
+	for _, item := range actions {
+		action, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		href, ok := action["href"].(string)
+		if !ok {
+			continue
+		}
+		found = true
+
+		method, _ := action["method"].(string)
+		method = strings.ToUpper(method)
+		if method == "" {
+			method = http.MethodGet
+		}
+		contentType, _ := action["type"].(string)
+		if contentType == "" {
+			contentType = "application/x-www-form-urlencoded"
+		}
+
+		fields := make(map[string]interface{})
+		values := url.Values{}
+		if items, ok := action["fields"].([]interface{}); ok {
+			for _, item := range items {
+				field, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, _ := field["name"].(string)
+				if name == "" {
+					continue
+				}
+				fields[name] = field["value"]
+				values.Set(name, jsonFieldValue(field["value"]))
+			}
+		}
+
+		request := e.add(href, "siren-action")
+		if request == nil {
+			continue
+		}
+		request.Method = method
+		// actions which could change the state of the target are output only
+		request.SkipRequest = !navigation.IsSafeMethod(method)
+		if method == http.MethodGet {
+			if len(values) > 0 {
+				if parsed, err := url.Parse(request.URL); err == nil {
+					query := parsed.Query()
+					for name := range values {
+						query.Set(name, values.Get(name))
+					}
+					parsed.RawQuery = query.Encode()
+					request.URL = parsed.String()
+				}
+			}
+			continue
+		}
+		if strings.Contains(contentType, "json") {
+			request.Body, _ = jsoniter.MarshalToString(fields)
+		} else {
+			request.Body = values.Encode()
+		}
+		request.Headers = map[string]string{"Content-Type": contentType}
+	}
+	return found
+}
+
+// add resolves the link against the response url and adds a request for it
+func (e *jsonLinkExtractor) add(link, attribute string) *navigation.Request {
+	link = strings.TrimSpace(link)
+	// uri templates (e.g. /orders{?page}) are cut at their first expression
+	if index := strings.Index(link, "{"); index != -1 {
+		link = link[:index]
+	}
+	if link == "" || stringsutil.HasPrefixAnyI(link, "#", "javascript:", "data:", "mailto:", "tel:", "urn:") {
+		return nil
+	}
+	if e.attribute != "" {
+		attribute = e.attribute
+	}
+	request := navigation.NewNavigationRequestURLFromResponse(link, e.source, e.tag, attribute, e.resp)
+	if request.URL == "" {
+		return nil
+	}
+	key := attribute + ":" + request.URL
+	if _, ok := e.seen[key]; ok {
+		return nil
+	}
+	e.seen[key] = struct{}{}
+	e.requests = append(e.requests, request)
+	return request
+}
+
+// jsonFieldValue returns the string form of a field value
+func jsonFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		text, _ := jsoniter.MarshalToString(v)
+		return text
+	}
+}
+
+// sortedJSONKeys returns the keys of the object in order for stable results
+func sortedJSONKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
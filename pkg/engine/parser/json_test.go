@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONContentParser(t *testing.T) {
+	t.Run("hypermedia", func(t *testing.T) {
+		body := `{
+	"_links": {"self": {"href": "/v1/orders?page=1"}, "next": {"href": "orders?page=2"}, "find": {"href": "/v1/orders{?id}", "templated": true}, "curies": [{"href": "/docs/rels/{rel}"}]},
+	"links": {"related": "https://api.example.com/v1/customers/1"},
+	"@odata.nextLink": "https://api.example.com/v1/orders?$skip=20",
+	"data": [{"id": 1, "avatar": "https://cdn.example.com/a.png", "note": "not a url", "url": "items/1"}]
+}`
+		found := make(map[string]string)
+		for _, request := range jsonContentParser(newTestResponse("https://api.example.com/v1/orders?page=1", "application/hal+json", body)) {
+			found[request.URL] = request.Attribute
+		}
+		require.Equal(t, map[string]string{
+			"https://api.example.com/v1/orders?page=1":   "hal",
+			"https://api.example.com/v1/orders?page=2":   "hal",
+			"https://api.example.com/v1/orders":          "hal",
+			"https://api.example.com/docs/rels/":         "hal",
+			"https://api.example.com/v1/customers/1":     "links",
+			"https://api.example.com/v1/orders?$skip=20": "odata",
+			"https://cdn.example.com/a.png":              "value",
+			"https://api.example.com/v1/items/1":         "url",
+		}, found)
+	})
+
+	t.Run("siren", func(t *testing.T) {
+		body := `{
+	"links": [{"rel": ["self"], "href": "/v1/orders/42"}],
+	"actions": [
+		{"name": "add-item", "method": "POST", "href": "/v1/orders/42/items", "type": "application/json", "fields": [{"name": "productCode", "type": "text"}, {"name": "quantity", "type": "number", "value": 1}]},
+		{"name": "search", "href": "/v1/orders/search", "fields": [{"name": "q", "value": "term"}]}
+	]
+}`
+		requests := jsonContentParser(newTestResponse("https://api.example.com/v1/orders?page=1", "application/vnd.siren+json", body))
+		require.Len(t, requests, 3)
+
+		require.Equal(t, http.MethodPost, requests[0].Method)
+		require.Equal(t, "https://api.example.com/v1/orders/42/items", requests[0].URL)
+		require.JSONEq(t, `{"productCode":null,"quantity":1}`, requests[0].Body)
+		require.Equal(t, "application/json", requests[0].Headers["Content-Type"])
+		require.True(t, requests[0].SkipRequest)
+
+		require.Equal(t, http.MethodGet, requests[1].Method)
+		require.Equal(t, "https://api.example.com/v1/orders/search?q=term", requests[1].URL)
+		require.False(t, requests[1].SkipRequest)
+
+		require.Equal(t, "https://api.example.com/v1/orders/42", requests[2].URL)
+		require.Equal(t, "links", requests[2].Attribute)
+	})
+
+	t.Run("non-json", func(t *testing.T) {
+		require.Empty(t, jsonContentParser(newTestResponse("https://api.example.com/v1/orders?page=1", "text/html", `{"url": "/a"}`)))
+		require.Empty(t, jsonContentParser(newTestResponse("https://api.example.com/v1/orders?page=1", "application/json", `not json`)))
+	})
+}
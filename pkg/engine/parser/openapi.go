@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+)
+
+var (
+	// openAPIUIMarkers are the markers of swagger ui, redoc and rapidoc pages and scripts
+	openAPIUIMarkers = []string{"SwaggerUIBundle", "SwaggerUIStandalonePreset", "Redoc.init", "spec-url"}
+	// swaggerUIConfigRegex matches the specification urls of swagger ui configurations
+	swaggerUIConfigRegex = regexp.MustCompile("\\b(?:url|configUrl)\\s*:\\s*[\"'`]([^\"'`\\s]+)[\"'`]")
+	// redocInitRegex matches the specification url of Redoc.init calls
+	redocInitRegex = regexp.MustCompile("Redoc\\.init\\(\\s*[\"'`]([^\"'`\\s]+)[\"'`]")
+	// specURLAttributeRegex matches the spec-url attribute of redoc and rapidoc elements
+	specURLAttributeRegex = regexp.MustCompile(`spec-url\s*=\s*["']([^"']+)["']`)
+)
+
+// openAPIParser expands the operations of OpenAPI and Swagger specifications
+// into requests with their method, parameters and body, the state changing
+// operations are written to the output without being requested.
+func openAPIParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
+	contentType := resp.Resp.Header.Get("Content-Type")
+	if strings.Contains(contentType, "html") || strings.Contains(contentType, "javascript") {
+		return
+	}
+	spec, err := utils.ParseOpenAPI(resp.Body)
+	if err != nil {
+		return
+	}
+	for _, operation := range spec.Operations(resp.Resp.Request.URL.String()) {
+		request := navigation.NewNavigationRequestURLFromResponse(operation.URL, resp.Resp.Request.URL.String(), "openapi", "operation", resp)
+		request.Method = operation.Method
+		request.Body = operation.Body
+		request.SkipRequest = !navigation.IsSafeMethod(operation.Method)
+		if len(operation.Headers) > 0 {
+			request.Headers = operation.Headers
+		}
+		navigationRequests = append(navigationRequests, request)
+	}
+	return
+}
+
+// openAPIUIParser parses the specification urls of swagger ui, redoc and
+// rapidoc pages and of their initializer scripts.
+func openAPIUIParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
+	hasMarker := false
+	for _, marker := range openAPIUIMarkers {
+		if strings.Contains(resp.Body, marker) {
+			hasMarker = true
+			break
+		}
+	}
+	if !hasMarker {
+		return
+	}
+
+	var values []string
+	if strings.Contains(resp.Body, "SwaggerUI") {
+		for _, match := range swaggerUIConfigRegex.FindAllStringSubmatch(resp.Body, -1) {
+			values = append(values, match[1])
+		}
+	}
+	for _, regex := range []*regexp.Regexp{redocInitRegex, specURLAttributeRegex} {
+		for _, match := range regex.FindAllStringSubmatch(resp.Body, -1) {
+			values = append(values, match[1])
+		}
+	}
+	for _, value := range values {
+		navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(value, resp.Resp.Request.URL.String(), "openapi", "ui", resp))
+	}
+	return
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIParsers(t *testing.T) {
+	t.Run("specification", func(t *testing.T) {
+		body := `{"openapi": "3.1.0", "paths": {"/pets/{petId}": {"get": {}, "patch": {"requestBody": {"content": {"application/json": {"example": {"name": "rex"}}}}}}}}`
+		requests := openAPIParser(newTestResponse("https://example.com/api/openapi.json", "application/json", body))
+		require.Len(t, requests, 2)
+
+		require.Equal(t, http.MethodGet, requests[0].Method)
+		require.Equal(t, "https://example.com/pets/1", requests[0].URL)
+		require.Equal(t, "openapi", requests[0].Tag)
+		require.False(t, requests[0].SkipRequest)
+
+		require.Equal(t, http.MethodPatch, requests[1].Method)
+		require.Equal(t, `{"name":"rex"}`, requests[1].Body)
+		require.Equal(t, "application/json", requests[1].Headers["Content-Type"])
+		require.True(t, requests[1].SkipRequest, "state changing operations should not be requested")
+
+		require.Empty(t, openAPIParser(newTestResponse("https://example.com/", "text/html", `<pre>{"openapi": "3.0.0", "paths": {"/x": {"get": {}}}}</pre>`)))
+	})
+
+	t.Run("ui", func(t *testing.T) {
+		script := `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    urls: [{url: "/v3/api-docs/public", name: "public"}, {url: "/v3/api-docs/internal", name: "internal"}],
+    dom_id: '#swagger-ui',
+  });
+};`
+		var urls []string
+		for _, request := range openAPIUIParser(newTestResponse("https://example.com/swagger-ui/swagger-initializer.js", "application/javascript", script)) {
+			urls = append(urls, request.URL)
+		}
+		require.Equal(t, []string{"https://example.com/v3/api-docs/public", "https://example.com/v3/api-docs/internal"}, urls)
+
+		requests := openAPIUIParser(newTestResponse("https://example.com/docs/", "text/html", `<redoc spec-url="openapi.yaml"></redoc>`))
+		require.Len(t, requests, 1)
+		require.Equal(t, "https://example.com/docs/openapi.yaml", requests[0].URL)
+	})
+}
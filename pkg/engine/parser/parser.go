@@ -3,86 +3,236 @@ package parser
 import (
 	"mime/multipart"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/katana/pkg/navigation"
 	"github.com/projectdiscovery/katana/pkg/output"
 	"github.com/projectdiscovery/katana/pkg/utils"
+	"github.com/projectdiscovery/utils/errkit"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 	urlutil "github.com/projectdiscovery/utils/url"
 	"golang.org/x/net/html"
 )
 
-// responseParserFunc is a function that parses the document returning
+// ResponseParserFunc is a function that parses the document returning
 // new navigation items or requests for the crawler.
 type ResponseParserFunc func(resp *navigation.Response) []*navigation.Request
 
 type Parser []responseParser
 
-type responseParserType int
+// ResponseParserType is the part of the response a parser works on
+type ResponseParserType int
 
 const (
-	headerParser responseParserType = iota + 1
-	bodyParser
-	contentParser
+	// HeaderParser parsers are run on responses with headers
+	HeaderParser ResponseParserType = iota + 1
+	// BodyParser parsers are run on responses with a parsed html document
+	BodyParser
+	// ContentParser parsers are run on responses with a non-empty body
+	ContentParser
 )
 
 type responseParser struct {
-	parserType responseParserType
+	name       string
+	parserType ResponseParserType
 	parserFunc ResponseParserFunc
 }
 
-func NewResponseParser() *Parser {
-	return &Parser{
+// optionalParser is a parser only enabled by an option, or if selected by name
+type optionalParser struct {
+	responseParser
+	enabled func(options *Options) bool
+}
+
+// Options contains the options of the response parsers
+type Options struct {
+	AutomaticFormFill      bool
+	ScrapeJSLuiceResponses bool
+	ScrapeJSResponses      bool
+	DisableRedirects       bool
+	// DocumentCrawl enables the parsing of pdf and office documents
+	DocumentCrawl bool
+	// OpenAPI enables the expansion of OpenAPI and Swagger specifications
+	OpenAPI bool
+	// GraphQL enables the detection and operation enumeration of graphql endpoints
+	GraphQL bool
+	// GraphQLQueries enables requesting the read-only queries of graphql endpoints
+	GraphQLQueries bool
+	// SourceMaps enables following and parsing the source maps of javascript and css files
+	SourceMaps bool
+	// Parsers are the names of the only parsers to run, including optional ones
+	Parsers []string
+	// DisabledParsers are the names of the parsers not to run
+	DisabledParsers []string
+}
+
+var (
+	registryMutex sync.RWMutex
+	// registeredParsers are the custom parsers registered from library code
+	registeredParsers []responseParser
+)
+
+// defaultParsers returns the parsers enabled by default
+func defaultParsers() []responseParser {
+	return []responseParser{
 		// Header based parsers
-		{headerParser, headerContentLocationParser},
-		{headerParser, headerLinkParser},
-		{headerParser, headerRefreshParser},
+		{"header-content-location", HeaderParser, headerContentLocationParser},
+		{"header-link", HeaderParser, headerLinkParser},
+		{"header-refresh", HeaderParser, headerRefreshParser},
 
 		// Body based parsers
-		{bodyParser, bodyATagParser},
-		{bodyParser, bodyLinkHrefTagParser},
-		{bodyParser, bodyBackgroundTagParser},
-		{bodyParser, bodyAudioTagParser},
-		{bodyParser, bodyAppletTagParser},
-		{bodyParser, bodyImgTagParser},
-		{bodyParser, bodyObjectTagParser},
-		{bodyParser, bodySvgTagParser},
-		{bodyParser, bodyTableTagParser},
-		{bodyParser, bodyVideoTagParser},
-		{bodyParser, bodyButtonFormactionTagParser},
-		{bodyParser, bodyBlockquoteCiteTagParser},
-		{bodyParser, bodyFrameSrcTagParser},
-		{bodyParser, bodyMapAreaPingTagParser},
-		{bodyParser, bodyBaseHrefTagParser},
-		{bodyParser, bodyImportImplementationTagParser},
-		{bodyParser, bodyEmbedTagParser},
-		{bodyParser, bodyFrameTagParser},
-		{bodyParser, bodyIframeTagParser},
-		{bodyParser, bodyInputSrcTagParser},
-		{bodyParser, bodyIsindexActionTagParser},
-		{bodyParser, bodyScriptSrcTagParser},
-		{bodyParser, bodyMetaContentTagParser},
-		{bodyParser, bodyHtmlManifestTagParser},
-		{bodyParser, bodyHtmlDoctypeTagParser},
-		{bodyParser, bodyHtmxAttrParser},
+		{"a-href", BodyParser, bodyATagParser},
+		{"link-href", BodyParser, bodyLinkHrefTagParser},
+		{"body-background", BodyParser, bodyBackgroundTagParser},
+		{"audio-src", BodyParser, bodyAudioTagParser},
+		{"applet", BodyParser, bodyAppletTagParser},
+		{"img-src", BodyParser, bodyImgTagParser},
+		{"object", BodyParser, bodyObjectTagParser},
+		{"svg", BodyParser, bodySvgTagParser},
+		{"table-background", BodyParser, bodyTableTagParser},
+		{"video-src", BodyParser, bodyVideoTagParser},
+		{"button-formaction", BodyParser, bodyButtonFormactionTagParser},
+		{"blockquote-cite", BodyParser, bodyBlockquoteCiteTagParser},
+		{"frame-src", BodyParser, bodyFrameSrcTagParser},
+		{"area-ping", BodyParser, bodyMapAreaPingTagParser},
+		{"base-href", BodyParser, bodyBaseHrefTagParser},
+		{"import-implementation", BodyParser, bodyImportImplementationTagParser},
+		{"embed-src", BodyParser, bodyEmbedTagParser},
+		{"frame", BodyParser, bodyFrameTagParser},
+		{"iframe-src", BodyParser, bodyIframeTagParser},
+		{"input-src", BodyParser, bodyInputSrcTagParser},
+		{"isindex-action", BodyParser, bodyIsindexActionTagParser},
+		{"script-src", BodyParser, bodyScriptSrcTagParser},
+		{"meta-content", BodyParser, bodyMetaContentTagParser},
+		{"meta-link", BodyParser, bodyMetaLinkTagParser},
+		{"html-manifest", BodyParser, bodyHtmlManifestTagParser},
+		{"html-doctype", BodyParser, bodyHtmlDoctypeTagParser},
+		{"htmx", BodyParser, bodyHtmxAttrParser},
+		{"js-handlers", BodyParser, bodyJSHandlersParser},
+		{"css-inline", BodyParser, cssInlineParser},
+		{"inline-data", BodyParser, inlineDataParser},
+
+		// Content based parsers
+		{"css-file", ContentParser, cssFileParser},
+		{"json", ContentParser, jsonContentParser},
+		{"xml", ContentParser, xmlContentParser},
 
 		// custom field regex parser
-		{bodyParser, customFieldRegexParser},
+		{"custom-field", BodyParser, customFieldRegexParser},
+	}
+}
+
+// commonOptionalParsers are the optional parsers available on all the platforms
+var commonOptionalParsers = []optionalParser{
+	{responseParser{"form", BodyParser, bodyFormTagParser}, func(options *Options) bool { return options.AutomaticFormFill }},
+	{responseParser{"js-regex-script", BodyParser, scriptContentRegexParser}, func(options *Options) bool { return options.ScrapeJSResponses }},
+	{responseParser{"js-regex-file", ContentParser, scriptJSFileRegexParser}, func(options *Options) bool { return options.ScrapeJSResponses }},
+	{responseParser{"endpoints-regex", ContentParser, bodyScrapeEndpointsParser}, func(options *Options) bool { return options.ScrapeJSResponses }},
+	{responseParser{"header-location", HeaderParser, headerLocationParser}, func(options *Options) bool { return !options.DisableRedirects }},
+	{responseParser{"document", ContentParser, documentContentParser}, func(options *Options) bool { return options.DocumentCrawl }},
+	{responseParser{"openapi", ContentParser, openAPIParser}, func(options *Options) bool { return options.OpenAPI }},
+	{responseParser{"openapi-ui", ContentParser, openAPIUIParser}, func(options *Options) bool { return options.OpenAPI }},
+	{responseParser{"graphql-endpoint", ContentParser, graphQLEndpointParser}, func(options *Options) bool { return options.GraphQL }},
+	{responseParser{"graphql", ContentParser, graphQLOperationsParser}, func(options *Options) bool { return options.GraphQL && !options.GraphQLQueries }},
+	{responseParser{"graphql-queries", ContentParser, graphQLQueriesParser}, func(options *Options) bool { return options.GraphQLQueries }},
+	{responseParser{"sourcemap-url", ContentParser, sourceMapURLParser}, func(options *Options) bool { return options.SourceMaps }},
+	{responseParser{"sourcemap-regex", ContentParser, sourceMapRegexParser}, func(options *Options) bool { return options.SourceMaps }},
+}
+
+// optionalParsers returns the optional parsers of the platform
+func optionalParsers() []optionalParser {
+	return slices.Concat(commonOptionalParsers, platformOptionalParsers)
+}
+
+// NewResponseParser returns the default and registered parsers, the optional
+// ones are added according to the options by InitWithOptions.
+func NewResponseParser() *Parser {
+	parser := Parser(defaultParsers())
+
+	registryMutex.RLock()
+	parser = append(parser, registeredParsers...)
+	registryMutex.RUnlock()
+	return &parser
+}
+
+// InitWithOptions adds the optional parsers enabled by the options and
+// restricts the parsers to the selected and not disabled ones.
+func (p *Parser) InitWithOptions(options *Options) {
+	for _, optional := range optionalParsers() {
+		if optional.enabled(options) || slices.Contains(options.Parsers, optional.name) {
+			*p = append(*p, optional.responseParser)
+		}
 	}
+
+	filtered := (*p)[:0]
+	for _, parser := range *p {
+		if len(options.Parsers) > 0 && !slices.Contains(options.Parsers, parser.name) {
+			continue
+		}
+		if slices.Contains(options.DisabledParsers, parser.name) {
+			continue
+		}
+		filtered = append(filtered, parser)
+	}
+	*p = filtered
+}
+
+// Register registers a custom response parser under a unique name. It is run
+// by the parsers created afterwards unless disabled by name in the options.
+func Register(name string, parserType ResponseParserType, parserFunc ResponseParserFunc) error {
+	if name == "" || parserFunc == nil {
+		return errkit.New("parser: name and function are required")
+	}
+	if parserType < HeaderParser || parserType > ContentParser {
+		return errkit.Newf("parser: invalid type %d for parser %s", parserType, name)
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if slices.Contains(builtinNames(), name) || slices.ContainsFunc(registeredParsers, func(parser responseParser) bool { return parser.name == name }) {
+		return errkit.Newf("parser: %s is already registered", name)
+	}
+	registeredParsers = append(registeredParsers, responseParser{name: name, parserType: parserType, parserFunc: parserFunc})
+	return nil
+}
+
+// Names returns the names of the builtin and registered parsers
+func Names() []string {
+	names := builtinNames()
+
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	for _, parser := range registeredParsers {
+		names = append(names, parser.name)
+	}
+	return names
+}
+
+// builtinNames returns the names of the default and optional parsers
+func builtinNames() []string {
+	var names []string
+	for _, parser := range defaultParsers() {
+		names = append(names, parser.name)
+	}
+	for _, optional := range optionalParsers() {
+		names = append(names, optional.name)
+	}
+	return names
 }
 
 // parseResponse runs the response parsers on the navigation response
 func (p *Parser) ParseResponse(resp *navigation.Response) (navigationRequests []*navigation.Request) {
 	for _, parser := range *p {
 		switch {
-		case parser.parserType == headerParser && resp.Resp != nil:
+		case parser.parserType == HeaderParser && resp.Resp != nil:
 			navigationRequests = append(navigationRequests, parser.parserFunc(resp)...)
-		case parser.parserType == bodyParser && resp.Reader != nil:
+		case parser.parserType == BodyParser && resp.Reader != nil:
 			navigationRequests = append(navigationRequests, parser.parserFunc(resp)...)
-		case parser.parserType == contentParser && len(resp.Body) > 0:
+		case parser.parserType == ContentParser && len(resp.Body) > 0:
 			navigationRequests = append(navigationRequests, parser.parserFunc(resp)...)
 		}
 	}
@@ -103,15 +253,17 @@ func headerContentLocationParser(resp *navigation.Response) (navigationRequests
 	return
 }
 
-// headerLinkParser parsers Link header from response
+// headerLinkParser parsers Link header from response with the relation
+// metadata of the links
 func headerLinkParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
 	header := resp.Resp.Header.Get("Link")
 	if header == "" {
 		return
 	}
-	values := utils.ParseLinkTag(header)
-	for _, value := range values {
-		navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(value, resp.Resp.Request.URL.String(), "header", "link", resp))
+	for _, value := range utils.ParseLinkHeader(header) {
+		request := navigation.NewNavigationRequestURLFromResponse(value.URL, resp.Resp.Request.URL.String(), "header", "link", resp)
+		request.Link = navigation.NewLink(value.Params["rel"], value.Params["hreflang"], value.Params["type"], value.Params["media"])
+		navigationRequests = append(navigationRequests, request)
 	}
 	return
 }
@@ -149,7 +301,9 @@ func bodyATagParser(resp *navigation.Response) (navigationRequests []*navigation
 	resp.Reader.Find("a").Each(func(i int, item *goquery.Selection) {
 		href, ok := item.Attr("href")
 		if ok && href != "" {
-			navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(href, resp.Resp.Request.URL.String(), "a", "href", resp))
+			request := navigation.NewNavigationRequestURLFromResponse(href, resp.Resp.Request.URL.String(), "a", "href", resp)
+			request.Link = navigation.NewLink(item.AttrOr("rel", ""), item.AttrOr("hreflang", ""), item.AttrOr("type", ""), "")
+			navigationRequests = append(navigationRequests, request)
 		}
 		ping, ok := item.Attr("ping")
 		if ok && ping != "" {
@@ -159,12 +313,15 @@ func bodyATagParser(resp *navigation.Response) (navigationRequests []*navigation
 	return
 }
 
-// bodyLinkHrefTagParser parses link tag from response
+// bodyLinkHrefTagParser parses link tag from response with its relation,
+// language, type and media, e.g. canonical, amphtml and hreflang alternates.
 func bodyLinkHrefTagParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
 	resp.Reader.Find("link[href]").Each(func(i int, item *goquery.Selection) {
 		href, ok := item.Attr("href")
 		if ok && href != "" {
-			navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(href, resp.Resp.Request.URL.String(), "link", "href", resp))
+			request := navigation.NewNavigationRequestURLFromResponse(href, resp.Resp.Request.URL.String(), "link", "href", resp)
+			request.Link = navigation.NewLink(item.AttrOr("rel", ""), item.AttrOr("hreflang", ""), item.AttrOr("type", ""), item.AttrOr("media", ""))
+			navigationRequests = append(navigationRequests, request)
 		}
 	})
 	return
@@ -591,6 +748,31 @@ func bodyFormTagParser(resp *navigation.Response) (navigationRequests []*navigat
 	return
 }
 
+// metaLinkProperties are the Open Graph, Twitter card and App Links properties
+// of meta tags whose content is a url
+var metaLinkProperties = map[string]struct{}{
+	"og:url": {}, "og:image": {}, "og:image:url": {}, "og:image:secure_url": {},
+	"og:video": {}, "og:video:url": {}, "og:video:secure_url": {},
+	"og:audio": {}, "og:audio:url": {}, "og:audio:secure_url": {},
+	"twitter:url": {}, "twitter:image": {}, "twitter:image:src": {}, "twitter:player": {}, "twitter:player:stream": {},
+	"al:web:url": {},
+}
+
+// bodyMetaLinkTagParser parses the Open Graph, Twitter card and App Links
+// urls of meta tags, relative ones included.
+func bodyMetaLinkTagParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
+	resp.Reader.Find("meta[content]").Each(func(i int, item *goquery.Selection) {
+		property := strings.ToLower(strings.TrimSpace(item.AttrOr("property", item.AttrOr("name", ""))))
+		if _, ok := metaLinkProperties[property]; !ok {
+			return
+		}
+		if content := strings.TrimSpace(item.AttrOr("content", "")); content != "" {
+			navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(content, resp.Resp.Request.URL.String(), "meta", property, resp))
+		}
+	})
+	return
+}
+
 // bodyMetaContentTagParser parses meta content tag from response
 func bodyMetaContentTagParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
 	resp.Reader.Find("meta").Each(func(i int, item *goquery.Selection) {
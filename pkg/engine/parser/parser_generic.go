@@ -12,29 +12,11 @@ import (
 	stringsutil "github.com/projectdiscovery/utils/strings"
 )
 
-type Options struct {
-	AutomaticFormFill      bool
-	ScrapeJSLuiceResponses bool
-	ScrapeJSResponses      bool
-	DisableRedirects       bool
-}
-
-func (p *Parser) InitWithOptions(options *Options) {
-	if options.AutomaticFormFill {
-		*p = append(*p, responseParser{bodyParser, bodyFormTagParser})
-	}
-	if options.ScrapeJSLuiceResponses {
-		*p = append(*p, responseParser{bodyParser, scriptContentJsluiceParser})
-		*p = append(*p, responseParser{contentParser, scriptJSFileJsluiceParser})
-	}
-	if options.ScrapeJSResponses {
-		*p = append(*p, responseParser{bodyParser, scriptContentRegexParser})
-		*p = append(*p, responseParser{contentParser, scriptJSFileRegexParser})
-		*p = append(*p, responseParser{contentParser, bodyScrapeEndpointsParser})
-	}
-	if !options.DisableRedirects {
-		*p = append(*p, responseParser{headerParser, headerLocationParser})
-	}
+// platformOptionalParsers are the optional parsers only available on this platform
+var platformOptionalParsers = []optionalParser{
+	{responseParser{"jsluice-script", BodyParser, scriptContentJsluiceParser}, func(options *Options) bool { return options.ScrapeJSLuiceResponses }},
+	{responseParser{"jsluice-file", ContentParser, scriptJSFileJsluiceParser}, func(options *Options) bool { return options.ScrapeJSLuiceResponses }},
+	{responseParser{"sourcemap-jsluice", ContentParser, sourceMapJsluiceParser}, func(options *Options) bool { return options.SourceMaps && options.ScrapeJSLuiceResponses }},
 }
 
 // scriptContentJsluiceParser parses script content endpoints using jsluice from response
@@ -72,3 +54,20 @@ func scriptJSFileJsluiceParser(resp *navigation.Response) (navigationRequests []
 	}
 	return
 }
+
+// sourceMapJsluiceParser parses endpoints using jsluice from the original sources of source maps
+func sourceMapJsluiceParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
+	sourceMap := responseSourceMap(resp)
+	if sourceMap == nil {
+		return
+	}
+	for _, file := range sourceMap.Files() {
+		if file.Content == "" || file.IsVendor() {
+			continue
+		}
+		for _, item := range utils.ExtractJsluiceEndpoints(file.Content) {
+			navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(item.Endpoint, resp.Resp.Request.URL.String(), "js", fmt.Sprintf("sourcemap-jsluice-%s", item.Type), resp))
+		}
+	}
+	return
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
 	"testing"
 
@@ -13,6 +14,12 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// newTestResponse returns a get response of the url with the content type and body
+func newTestResponse(rawURL, contentType, body string) *navigation.Response {
+	parsed, _ := urlutil.Parse(rawURL)
+	return &navigation.Response{Resp: &http.Response{Request: &http.Request{Method: http.MethodGet, URL: parsed.URL}, Header: http.Header{"Content-Type": []string{contentType}}}, Body: body}
+}
+
 func TestHeaderParsers(t *testing.T) {
 	parsed, _ := urlutil.Parse("https://security-crawl-maze.app/headers/xyz/")
 
@@ -25,6 +32,14 @@ func TestHeaderParsers(t *testing.T) {
 		resp := &navigation.Response{Resp: &http.Response{Request: &http.Request{URL: parsed.URL}, Header: http.Header{"Link": []string{"</test/headers/link.found>; rel=\"preload\""}}}}
 		navigationRequests := headerLinkParser(resp)
 		require.Equal(t, "https://security-crawl-maze.app/test/headers/link.found", navigationRequests[0].URL, "could not get correct url")
+		require.Equal(t, &navigation.Link{Rel: "preload"}, navigationRequests[0].Link)
+
+		resp = &navigation.Response{Resp: &http.Response{Request: &http.Request{URL: parsed.URL}, Header: http.Header{"Link": []string{`</de/start>; rel="alternate"; hreflang=de, </search?a=1,2>; rel=alternate; type="application/rss+xml"; title="a, b"`}}}}
+		navigationRequests = headerLinkParser(resp)
+		require.Len(t, navigationRequests, 2)
+		require.Equal(t, &navigation.Link{Rel: "alternate", Hreflang: "de"}, navigationRequests[0].Link)
+		require.Equal(t, "https://security-crawl-maze.app/search?a=1,2", navigationRequests[1].URL)
+		require.Equal(t, &navigation.Link{Rel: "alternate", Type: "application/rss+xml"}, navigationRequests[1].Link)
 	})
 	t.Run("location", func(t *testing.T) {
 		resp := &navigation.Response{Resp: &http.Response{Request: &http.Request{URL: parsed.URL}, Header: http.Header{"Location": []string{"http://security-crawl-maze.app/test/headers/location.found"}}}}
@@ -251,6 +266,29 @@ func TestBodyParsers(t *testing.T) {
 		resp = &navigation.Response{Resp: &http.Response{Request: &http.Request{URL: parsed.URL}}, Reader: documentReader}
 		navigationRequests = bodyLinkHrefTagParser(resp)
 		require.Equal(t, "https://security-crawl-maze.app/test/html/head/link/href.found", navigationRequests[0].URL, "could not get correct url")
+
+		documentReader, _ = goquery.NewDocumentFromReader(strings.NewReader(`<link rel="canonical" href="/en/"><link rel="Alternate" hreflang="fr-CA" href="/fr-ca/"><link rel="alternate" media="only screen and (max-width: 640px)" href="https://m.example.com/"><link rel="amphtml" href="/en/amp/">`))
+		resp = &navigation.Response{Resp: &http.Response{Request: &http.Request{URL: parsed.URL}}, Reader: documentReader}
+		navigationRequests = bodyLinkHrefTagParser(resp)
+		require.Len(t, navigationRequests, 4)
+		require.Equal(t, &navigation.Link{Rel: "canonical"}, navigationRequests[0].Link)
+		require.Equal(t, &navigation.Link{Rel: "alternate", Hreflang: "fr-CA"}, navigationRequests[1].Link)
+		require.Equal(t, &navigation.Link{Rel: "alternate", Media: "only screen and (max-width: 640px)"}, navigationRequests[2].Link)
+		require.Equal(t, "https://security-crawl-maze.app/en/amp/", navigationRequests[3].URL)
+		require.Equal(t, &navigation.Link{Rel: "amphtml"}, navigationRequests[3].Link)
+	})
+	t.Run("meta-link", func(t *testing.T) {
+		documentReader, _ := goquery.NewDocumentFromReader(strings.NewReader(`<meta property="og:url" content="https://security-crawl-maze.app/en/"><meta property="og:image" content="/img/share.png"><meta name="twitter:player" content="/embed/video"><meta property="og:title" content="/not/a/link"><meta name="description" content="maze">`))
+		resp := &navigation.Response{Resp: &http.Response{Request: &http.Request{URL: parsed.URL}}, Reader: documentReader}
+		var results []string
+		for _, request := range bodyMetaLinkTagParser(resp) {
+			results = append(results, request.Attribute+" "+request.URL)
+		}
+		require.Equal(t, []string{
+			"og:url https://security-crawl-maze.app/en/",
+			"og:image https://security-crawl-maze.app/img/share.png",
+			"twitter:player https://security-crawl-maze.app/embed/video",
+		}, results)
 	})
 	t.Run("base", func(t *testing.T) {
 		documentReader, _ := goquery.NewDocumentFromReader(strings.NewReader(`<base href="/test/html/head/base/href.found">`))
@@ -523,3 +561,51 @@ func TestHtmxBodyParser(t *testing.T) {
 		require.Equal(t, "PATCH", navigationRequests[0].Method, "could not get correct method")
 	})
 }
+
+// unregister removes a custom parser from the registry
+func unregister(name string) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registeredParsers = slices.DeleteFunc(registeredParsers, func(parser responseParser) bool { return parser.name == name })
+}
+
+func TestParserRegistry(t *testing.T) {
+	names := func(p *Parser) []string {
+		var values []string
+		for _, parser := range *p {
+			values = append(values, parser.name)
+		}
+		return values
+	}
+
+	custom := func(resp *navigation.Response) []*navigation.Request {
+		return []*navigation.Request{navigation.NewNavigationRequestURLFromResponse("/custom", resp.Resp.Request.URL.String(), "div", "data-custom", resp)}
+	}
+	require.Nil(t, Register("test-custom", BodyParser, custom), "could not register parser")
+	t.Cleanup(func() { unregister("test-custom") })
+	require.NotNil(t, Register("test-custom", BodyParser, custom), "duplicate parser was registered")
+	require.NotNil(t, Register("a-href", BodyParser, custom), "builtin parser was overridden")
+	require.NotNil(t, Register("test-invalid", ResponseParserType(42), custom), "parser with invalid type was registered")
+	require.Contains(t, Names(), "test-custom")
+
+	t.Run("default", func(t *testing.T) {
+		p := NewResponseParser()
+		p.InitWithOptions(&Options{DisabledParsers: []string{"htmx"}})
+		require.Contains(t, names(p), "test-custom")
+		require.Contains(t, names(p), "header-location")
+		require.NotContains(t, names(p), "htmx")
+		require.NotContains(t, names(p), "form")
+	})
+
+	t.Run("selected", func(t *testing.T) {
+		p := NewResponseParser()
+		p.InitWithOptions(&Options{Parsers: []string{"a-href", "form", "test-custom"}, DisabledParsers: []string{"a-href"}})
+		require.ElementsMatch(t, []string{"form", "test-custom"}, names(p))
+
+		parsed, _ := urlutil.Parse("https://example.com/")
+		documentReader, _ := goquery.NewDocumentFromReader(strings.NewReader(`<a href="/link">link</a>`))
+		navigationRequests := p.ParseResponse(&navigation.Response{Resp: &http.Response{Request: &http.Request{URL: parsed.URL}}, Reader: documentReader})
+		require.Len(t, navigationRequests, 1)
+		require.Equal(t, "https://example.com/custom", navigationRequests[0].URL)
+	})
+}
@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"encoding/base64"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+)
+
+// sourceMapCommentRegex matches the source map comments of javascript and css files
+var sourceMapCommentRegex = regexp.MustCompile(`[#@]\s*sourceMappingURL=([^\s'"*]+)`)
+
+// sourceMapHeaders are the headers referencing the source map of a response
+var sourceMapHeaders = []string{"SourceMap", "X-SourceMap"}
+
+// sourceMapURLParser parses the source map urls of javascript and css files
+// from their sourceMappingURL comment and SourceMap headers.
+func sourceMapURLParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
+	tag := sourceMapReferrerTag(resp)
+	if tag == "" {
+		return
+	}
+	for _, value := range sourceMapReferences(resp) {
+		// inline source maps are parsed by the source map parsers
+		if stringsutil.HasPrefixI(value, "data:") {
+			continue
+		}
+		navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(value, resp.Resp.Request.URL.String(), tag, "sourcemap", resp))
+	}
+	return
+}
+
+// sourceMapRegexParser parses relative endpoints from the original sources of
+// source maps, and the urls of the sources and sections they reference.
+func sourceMapRegexParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
+	sourceMap := responseSourceMap(resp)
+	if sourceMap == nil {
+		return
+	}
+	source := resp.Resp.Request.URL.String()
+	for _, value := range sourceMap.SectionURLs() {
+		navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(value, source, "js", "sourcemap-section", resp))
+	}
+	for _, file := range sourceMap.Files() {
+		if file.IsVendor() {
+			continue
+		}
+		// sources which are not embedded may be served next to the source map
+		if file.Content == "" {
+			if !strings.Contains(file.Path, "://") || stringsutil.HasPrefixAnyI(file.Path, "http://", "https://") {
+				navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(file.Path, source, "js", "sourcemap-source", resp))
+			}
+			continue
+		}
+		for _, item := range utils.ExtractRelativeEndpoints(file.Content) {
+			navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(item, source, "js", "sourcemap-regex", resp))
+		}
+	}
+	return
+}
+
+// responseSourceMap returns the source map of a source map response, or the
+// inline source map of a javascript or css response.
+func responseSourceMap(resp *navigation.Response) *utils.SourceMap {
+	if sourceMapReferrerTag(resp) != "" {
+		for _, value := range sourceMapReferences(resp) {
+			if data, ok := decodeDataURL(value); ok {
+				if sourceMap, err := utils.ParseSourceMap(data); err == nil {
+					return sourceMap
+				}
+			}
+		}
+		return nil
+	}
+	if !strings.HasSuffix(strings.ToLower(resp.Resp.Request.URL.Path), ".map") && !isJSONResponse(resp) {
+		return nil
+	}
+	sourceMap, err := utils.ParseSourceMap(resp.Body)
+	if err != nil {
+		return nil
+	}
+	return sourceMap
+}
+
+// sourceMapReferrerTag returns the tag of javascript and css responses, which
+// can reference a source map, or an empty string for other responses.
+func sourceMapReferrerTag(resp *navigation.Response) string {
+	contentType := resp.Resp.Header.Get("Content-Type")
+	switch {
+	case stringsutil.HasSuffixAny(resp.Resp.Request.URL.Path, ".js", ".mjs") || strings.Contains(contentType, "/javascript") || strings.Contains(contentType, "/ecmascript"):
+		return "js"
+	case strings.HasSuffix(resp.Resp.Request.URL.Path, ".css") || strings.Contains(contentType, "text/css"):
+		return "css"
+	}
+	return ""
+}
+
+// sourceMapReferences returns the source map urls referenced by the headers
+// and the sourceMappingURL comment of a response.
+func sourceMapReferences(resp *navigation.Response) []string {
+	var values []string
+	for _, header := range sourceMapHeaders {
+		if value := strings.TrimSpace(resp.Resp.Header.Get(header)); value != "" {
+			values = append(values, value)
+		}
+	}
+	// only the last comment of the file applies
+	if matches := sourceMapCommentRegex.FindAllStringSubmatch(resp.Body, -1); len(matches) > 0 {
+		values = append(values, matches[len(matches)-1][1])
+	}
+	return values
+}
+
+// decodeDataURL returns the decoded data of a base64 or percent encoded data url
+func decodeDataURL(value string) (string, bool) {
+	if !stringsutil.HasPrefixI(value, "data:") {
+		return "", false
+	}
+	metadata, data, ok := strings.Cut(value[len("data:"):], ",")
+	if !ok {
+		return "", false
+	}
+	if strings.HasSuffix(strings.ToLower(metadata), ";base64") {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(data, "="))
+		}
+		return string(decoded), err == nil
+	}
+	decoded, err := url.PathUnescape(data)
+	return decoded, err == nil
+}
@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceMapParsers(t *testing.T) {
+	sourceMap := `{"version":3,"file":"app.min.js","sourceRoot":"","sources":["webpack:///./src/api/client.js","webpack:///./node_modules/lib/index.js","../src/legacy.js"],` +
+		`"sourcesContent":["const users = '/api/v2/internal/users';\nfetch('/api/v2/admin/audit')","fetch('/vendor/endpoint')",null],"mappings":"AAAA"}`
+
+	t.Run("references", func(t *testing.T) {
+		resp := newTestResponse("https://example.com/static/app.min.js", "application/javascript", "console.log(1);\n//# sourceMappingURL=app.min.js.map")
+		resp.Resp.Header.Set("Sourcemap", "/maps/app.js.map")
+		var urls []string
+		for _, request := range sourceMapURLParser(resp) {
+			require.Equal(t, "sourcemap", request.Attribute)
+			urls = append(urls, request.URL)
+		}
+		require.ElementsMatch(t, []string{"https://example.com/maps/app.js.map", "https://example.com/static/app.min.js.map"}, urls)
+
+		resp = newTestResponse("https://example.com/static/site.css", "", "body{}\n/*# sourceMappingURL=site.css.map */")
+		requests := sourceMapURLParser(resp)
+		require.Len(t, requests, 1)
+		require.Equal(t, "css", requests[0].Tag)
+		require.Equal(t, "https://example.com/static/site.css.map", requests[0].URL)
+	})
+
+	t.Run("sources", func(t *testing.T) {
+		found := make(map[string]string)
+		for _, request := range sourceMapRegexParser(newTestResponse("https://example.com/static/app.min.js.map", "", sourceMap)) {
+			found[request.URL] = request.Attribute
+		}
+		require.Equal(t, map[string]string{
+			"https://example.com/api/v2/internal/users": "sourcemap-regex",
+			"https://example.com/api/v2/admin/audit":    "sourcemap-regex",
+			"https://example.com/src/legacy.js":         "sourcemap-source",
+		}, found)
+	})
+
+	t.Run("inline", func(t *testing.T) {
+		body := "console.log(1);\n//# sourceMappingURL=data:application/json;charset=utf-8;base64," + base64.StdEncoding.EncodeToString([]byte(sourceMap))
+		resp := newTestResponse("https://example.com/static/app.js", "", body)
+		require.Empty(t, sourceMapURLParser(resp))
+		require.Len(t, sourceMapRegexParser(resp), 3)
+	})
+}
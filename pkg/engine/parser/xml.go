@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"encoding/xml"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/utils"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+)
+
+const (
+	xmlNamespace   = "http://www.w3.org/XML/1998/namespace"
+	xlinkNamespace = "http://www.w3.org/1999/xlink"
+)
+
+// xmlURLAttributes are the attributes whose value is a url even if relative,
+// e.g. atom <link href>, opml <outline xmlUrl> or rdf:resource.
+var xmlURLAttributes = map[string]struct{}{
+	"href": {}, "src": {}, "url": {}, "uri": {}, "xmlurl": {}, "htmlurl": {}, "resource": {}, "about": {}, "ref": {},
+}
+
+// xmlURLElements are the elements whose text is a url even if relative,
+// e.g. rss <link>, sitemap <loc> or rss <comments>.
+var xmlURLElements = map[string]struct{}{
+	"link": {}, "loc": {}, "url": {}, "uri": {}, "comments": {}, "commentrss": {}, "docs": {}, "icon": {}, "logo": {},
+}
+
+// xmlExtensions are the extensions of xml documents served without a content type
+var xmlExtensions = map[string]struct{}{
+	".xml": {}, ".rss": {}, ".atom": {}, ".opml": {}, ".rdf": {},
+}
+
+// xmlContentParser parses urls from xml responses such as rss, atom and opml
+// feeds by walking elements and attributes, honouring xml:base.
+func xmlContentParser(resp *navigation.Response) (navigationRequests []*navigation.Request) {
+	if !isXMLResponse(resp) {
+		return
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(resp.Body))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+
+	type element struct {
+		name string
+		base *url.URL
+		text strings.Builder
+	}
+	source := resp.Resp.Request.URL.String()
+	seen := make(map[string]struct{})
+	add := func(value string, base *url.URL, tag, attribute string, explicit bool) {
+		value = strings.TrimSpace(value)
+		if value == "" || stringsutil.HasPrefixAnyI(value, "#", "javascript:", "data:", "mailto:", "tel:", "urn:", "tag:") {
+			return
+		}
+		values := []string{value}
+		if !explicit {
+			values = utils.ExtractURLLikeValues(value)
+		}
+		for _, item := range values {
+			resolved, err := base.Parse(item)
+			if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+				continue
+			}
+			resolved.Fragment = ""
+			if _, ok := seen[resolved.String()]; ok {
+				continue
+			}
+			seen[resolved.String()] = struct{}{}
+			navigationRequests = append(navigationRequests, navigation.NewNavigationRequestURLFromResponse(resolved.String(), source, tag, attribute, resp))
+		}
+	}
+
+	stack := []*element{{base: resp.Resp.Request.URL}}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		parent := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.StartElement:
+			current := &element{name: t.Name.Local, base: parent.base}
+			for _, attr := range t.Attr {
+				if attr.Name.Local == "base" && (attr.Name.Space == xmlNamespace || attr.Name.Space == "xml") {
+					if resolved, err := parent.base.Parse(strings.TrimSpace(attr.Value)); err == nil {
+						current.base = resolved
+					}
+				}
+			}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" || attr.Name.Space == xmlNamespace {
+					continue
+				}
+				_, isURL := xmlURLAttributes[strings.ToLower(attr.Name.Local)]
+				isURL = isURL || attr.Name.Space == xlinkNamespace && attr.Name.Local == "href"
+				add(attr.Value, current.base, current.name, attr.Name.Local, isURL)
+			}
+			stack = append(stack, current)
+		case xml.CharData:
+			parent.text.Write(t)
+		case xml.EndElement:
+			if len(stack) == 1 {
+				continue
+			}
+			_, isURL := xmlURLElements[strings.ToLower(parent.name)]
+			add(parent.text.String(), parent.base, parent.name, "text", isURL)
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return
+}
+
+// isXMLResponse returns true if the response is an xml document
+func isXMLResponse(resp *navigation.Response) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Resp.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "application/xhtml+xml":
+		// xhtml documents are parsed by the body parsers
+		return false
+	case mediaType == "application/xml", mediaType == "text/xml", strings.HasSuffix(mediaType, "+xml"):
+		return true
+	case mediaType == "" || mediaType == "text/plain" || mediaType == "application/octet-stream":
+		_, ok := xmlExtensions[strings.ToLower(path.Ext(resp.Resp.Request.URL.Path))]
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXMLContentParser(t *testing.T) {
+	parse := func(resp *navigation.Response) map[string]string {
+		found := make(map[string]string)
+		for _, request := range xmlContentParser(resp) {
+			found[request.URL] = request.Tag + ":" + request.Attribute
+		}
+		return found
+	}
+
+	t.Run("rss", func(t *testing.T) {
+		body := `<?xml version="1.0"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+<channel>
+	<title>News</title>
+	<link>https://example.com/news/</link>
+	<atom:link href="/news/feed.xml" rel="self" type="application/rss+xml"/>
+	<item>
+		<title>First &amp; foremost</title>
+		<link>https://example.com/news/first</link>
+		<guid isPermaLink="false">tag:example.com,2024:1</guid>
+		<comments>/news/first#comments</comments>
+		<enclosure url="https://cdn.example.com/first.mp3" length="1" type="audio/mpeg"/>
+		<description><![CDATA[<p>Read <a href="https://example.com/news/more">more</a></p>]]></description>
+	</item>
+</channel>
+</rss>`
+		require.Equal(t, map[string]string{
+			"https://example.com/news/":         "link:text",
+			"https://example.com/news/feed.xml": "link:href",
+			"https://example.com/news/first":    "link:text",
+			"https://cdn.example.com/first.mp3": "enclosure:url",
+			"https://example.com/news/more":     "description:text",
+		}, parse(newTestResponse("https://example.com/feed", "application/rss+xml; charset=utf-8", body)))
+	})
+
+	t.Run("atom", func(t *testing.T) {
+		body := `<feed xmlns="http://www.w3.org/2005/Atom" xml:base="https://example.com/blog/">
+	<id>urn:uuid:60a76c80-d399-11d9-b93C-0003939e0af6</id>
+	<link rel="alternate" href="./"/>
+	<entry xml:base="2024/">
+		<link href="hello-world"/>
+		<author><uri>/authors/jane</uri></author>
+	</entry>
+</feed>`
+		require.Equal(t, map[string]string{
+			"https://example.com/blog/":                 "link:href",
+			"https://example.com/blog/2024/hello-world": "link:href",
+			"https://example.com/authors/jane":          "uri:text",
+		}, parse(newTestResponse("https://feeds.example.com/atom", "application/atom+xml", body)))
+	})
+
+	t.Run("opml", func(t *testing.T) {
+		body := `<opml version="2.0" xmlns:xlink="http://www.w3.org/1999/xlink">
+	<body>
+		<outline text="Engineering" xmlUrl="/engineering/rss" htmlUrl="/engineering"/>
+		<outline text="Docs" xlink:href="docs/index.xml"/>
+	</body>
+</opml>`
+		require.Equal(t, map[string]string{
+			"https://example.com/engineering/rss":      "outline:xmlUrl",
+			"https://example.com/engineering":          "outline:htmlUrl",
+			"https://example.com/lists/docs/index.xml": "outline:href",
+		}, parse(newTestResponse("https://example.com/lists/feeds.opml", "", body)))
+	})
+
+	t.Run("not xml", func(t *testing.T) {
+		require.Empty(t, parse(newTestResponse("https://example.com/", "application/xhtml+xml", `<html><a href="/x">x</a></html>`)))
+		require.Empty(t, parse(newTestResponse("https://example.com/", "text/html", `<link>https://example.com/y</link>`)))
+	})
+}
@@ -0,0 +1,84 @@
+package profile
+
+// chromeBrands are the client hints brands of the emulated chrome version
+var chromeBrands = []Brand{
+	{Brand: "Chromium", Version: "124"},
+	{Brand: "Google Chrome", Version: "124"},
+	{Brand: "Not-A.Brand", Version: "99"},
+}
+
+// builtinProfiles are the profiles selectable by name
+var builtinProfiles = map[string]Profile{
+	"desktop": {
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		Platform:  "Win32",
+		ClientHints: &ClientHints{
+			Brands:          chromeBrands,
+			FullVersion:     "124.0.6367.91",
+			Platform:        "Windows",
+			PlatformVersion: "15.0.0",
+			Architecture:    "x86",
+			Bitness:         "64",
+		},
+		Width:             1920,
+		Height:            1080,
+		DeviceScaleFactor: 1,
+		Locale:            "en-US",
+		Stealth:           true,
+		WebGLVendor:       "Google Inc. (NVIDIA)",
+		WebGLRenderer:     "ANGLE (NVIDIA, NVIDIA GeForce GTX 1650 Direct3D11 vs_5_0 ps_5_0, D3D11)",
+	},
+	"macos": {
+		UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		Platform:  "MacIntel",
+		ClientHints: &ClientHints{
+			Brands:          chromeBrands,
+			FullVersion:     "124.0.6367.91",
+			Platform:        "macOS",
+			PlatformVersion: "14.4.1",
+			Architecture:    "arm",
+			Bitness:         "64",
+		},
+		Width:             1440,
+		Height:            900,
+		DeviceScaleFactor: 2,
+		Locale:            "en-US",
+		Stealth:           true,
+		WebGLVendor:       "Google Inc. (Apple)",
+		WebGLRenderer:     "ANGLE (Apple, ANGLE Metal Renderer: Apple M1, Unspecified Version)",
+	},
+	"mobile": {
+		UserAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36",
+		Platform:  "Linux armv81",
+		ClientHints: &ClientHints{
+			Brands:          chromeBrands,
+			FullVersion:     "124.0.6367.82",
+			Platform:        "Android",
+			PlatformVersion: "14.0.0",
+			Model:           "Pixel 7",
+		},
+		Width:             412,
+		Height:            915,
+		DeviceScaleFactor: 2.625,
+		Mobile:            true,
+		Touch:             true,
+		Locale:            "en-US",
+		Stealth:           true,
+		WebGLVendor:       "Qualcomm",
+		WebGLRenderer:     "Adreno (TM) 730",
+	},
+	"iphone": {
+		// safari does not support user agent client hints
+		UserAgent:         "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+		Platform:          "iPhone",
+		Width:             390,
+		Height:            844,
+		DeviceScaleFactor: 3,
+		Mobile:            true,
+		Touch:             true,
+		Locale:            "en-US",
+		Stealth:           true,
+		WebGLVendor:       "Apple Inc.",
+		WebGLRenderer:     "Apple GPU",
+	},
+}
@@ -0,0 +1,163 @@
+// Package profile implements browser device, locale and stealth
+// profiles which are applied to the pages of headless crawling.
+package profile
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/projectdiscovery/utils/errkit"
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is a browser profile emulated by the headless pages
+type Profile struct {
+	// UserAgent is the user agent of the browser
+	UserAgent string `yaml:"user-agent"`
+	// Platform is the navigator.platform value
+	Platform string `yaml:"platform,omitempty"`
+	// ClientHints are the user agent client hints sent in the Sec-CH-UA
+	// headers, browsers not supporting client hints leave it empty.
+	ClientHints *ClientHints `yaml:"client-hints,omitempty"`
+	// Width is the viewport width
+	Width int `yaml:"width"`
+	// Height is the viewport height
+	Height int `yaml:"height"`
+	// DeviceScaleFactor is the device pixel ratio
+	DeviceScaleFactor float64 `yaml:"device-scale-factor,omitempty"`
+	// Mobile enables mobile emulation (viewport meta tag, overlay scrollbars, etc)
+	Mobile bool `yaml:"mobile,omitempty"`
+	// Touch enables touch events emulation
+	Touch bool `yaml:"touch,omitempty"`
+	// Locale is the locale of the browser, e.g. en-US
+	Locale string `yaml:"locale,omitempty"`
+	// AcceptLanguage is the Accept-Language header, derived from the locale if empty
+	AcceptLanguage string `yaml:"accept-language,omitempty"`
+	// Timezone is the IANA timezone of the browser, e.g. America/New_York
+	Timezone string `yaml:"timezone,omitempty"`
+	// Geolocation is the emulated position of the device
+	Geolocation *Geolocation `yaml:"geolocation,omitempty"`
+	// Stealth patches the browser properties revealing headless automation
+	Stealth bool `yaml:"stealth,omitempty"`
+	// WebGLVendor is the unmasked webgl vendor reported by stealth mode
+	WebGLVendor string `yaml:"webgl-vendor,omitempty"`
+	// WebGLRenderer is the unmasked webgl renderer reported by stealth mode
+	WebGLRenderer string `yaml:"webgl-renderer,omitempty"`
+}
+
+// ClientHints are the user agent client hints of a profile
+type ClientHints struct {
+	Brands          []Brand `yaml:"brands"`
+	FullVersion     string  `yaml:"full-version,omitempty"`
+	Platform        string  `yaml:"platform"`
+	PlatformVersion string  `yaml:"platform-version,omitempty"`
+	Architecture    string  `yaml:"architecture,omitempty"`
+	Bitness         string  `yaml:"bitness,omitempty"`
+	Model           string  `yaml:"model,omitempty"`
+}
+
+// Brand is a user agent brand and its major version
+type Brand struct {
+	Brand   string `yaml:"brand"`
+	Version string `yaml:"version"`
+}
+
+// Geolocation is an emulated device position
+type Geolocation struct {
+	Latitude  float64 `yaml:"latitude"`
+	Longitude float64 `yaml:"longitude"`
+	Accuracy  float64 `yaml:"accuracy,omitempty"`
+}
+
+// Get returns the built-in profile with the name or loads the profile
+// from the yaml file at the path if no built-in profile matches.
+func Get(nameOrFile string) (*Profile, error) {
+	if profile, ok := builtinProfiles[strings.ToLower(nameOrFile)]; ok {
+		return &profile, nil
+	}
+	data, err := os.ReadFile(nameOrFile)
+	if err != nil {
+		return nil, errkit.Newf("profile: unknown profile %q (available: %s)", nameOrFile, strings.Join(Names(), ","))
+	}
+	profile := &Profile{}
+	if err := yaml.Unmarshal(data, profile); err != nil {
+		return nil, errkit.Wrap(err, "profile: could not decode profile")
+	}
+	if err := profile.validate(); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Names returns the names of the built-in profiles
+func Names() []string {
+	names := make([]string, 0, len(builtinProfiles))
+	for name := range builtinProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p *Profile) validate() error {
+	if p.Width < 0 || p.Height < 0 {
+		return errkit.New("profile: width and height can't be negative")
+	}
+	if (p.Width == 0) != (p.Height == 0) {
+		return errkit.New("profile: both width and height must be specified")
+	}
+	if p.DeviceScaleFactor < 0 {
+		return errkit.New("profile: device scale factor can't be negative")
+	}
+	if p.Geolocation != nil && (p.Geolocation.Latitude < -90 || p.Geolocation.Latitude > 90 || p.Geolocation.Longitude < -180 || p.Geolocation.Longitude > 180) {
+		return errkit.New("profile: invalid geolocation coordinates")
+	}
+	return nil
+}
+
+// AcceptLanguageHeader returns the Accept-Language header of the profile
+func (p *Profile) AcceptLanguageHeader() string {
+	if p.AcceptLanguage != "" || p.Locale == "" {
+		return p.AcceptLanguage
+	}
+	language, _, found := strings.Cut(p.Locale, "-")
+	if !found {
+		return p.Locale
+	}
+	return p.Locale + "," + language + ";q=0.9"
+}
+
+// UserAgentOverride returns the user agent override of the profile including
+// the client hints metadata used by the browser for the Sec-CH-UA headers.
+func (p *Profile) UserAgentOverride() *proto.NetworkSetUserAgentOverride {
+	override := &proto.NetworkSetUserAgentOverride{
+		UserAgent:      p.UserAgent,
+		AcceptLanguage: p.AcceptLanguageHeader(),
+		Platform:       p.Platform,
+	}
+	if hints := p.ClientHints; hints != nil {
+		metadata := &proto.EmulationUserAgentMetadata{
+			FullVersion:     hints.FullVersion,
+			Platform:        hints.Platform,
+			PlatformVersion: hints.PlatformVersion,
+			Architecture:    hints.Architecture,
+			Bitness:         hints.Bitness,
+			Model:           hints.Model,
+			Mobile:          p.Mobile,
+		}
+		for _, brand := range hints.Brands {
+			metadata.Brands = append(metadata.Brands, &proto.EmulationUserAgentBrandVersion{Brand: brand.Brand, Version: brand.Version})
+			if hints.FullVersion != "" {
+				version := brand.Version
+				if strings.HasPrefix(hints.FullVersion, brand.Version+".") {
+					version = hints.FullVersion
+				}
+				metadata.FullVersionList = append(metadata.FullVersionList, &proto.EmulationUserAgentBrandVersion{Brand: brand.Brand, Version: version})
+			}
+		}
+		override.UserAgentMetadata = metadata
+	}
+	return override
+}
@@ -0,0 +1,77 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProfile(t *testing.T) {
+	t.Run("builtin", func(t *testing.T) {
+		profile, err := Get("Mobile")
+		require.Nil(t, err, "could not get builtin profile")
+		require.True(t, profile.Mobile, "builtin mobile profile is not mobile")
+		require.True(t, profile.Touch, "builtin mobile profile has no touch")
+	})
+
+	t.Run("file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "profile.yaml")
+		err := os.WriteFile(file, []byte(`user-agent: custom-agent
+width: 800
+height: 600
+locale: fr-FR
+timezone: Europe/Paris
+geolocation:
+  latitude: 48.85
+  longitude: 2.35
+`), 0644)
+		require.Nil(t, err, "could not write profile")
+
+		profile, err := Get(file)
+		require.Nil(t, err, "could not load profile")
+		require.Equal(t, "custom-agent", profile.UserAgent)
+		require.Equal(t, 800, profile.Width)
+		require.Equal(t, "Europe/Paris", profile.Timezone)
+		require.Equal(t, 48.85, profile.Geolocation.Latitude)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "profile.yaml")
+		err := os.WriteFile(file, []byte("width: 800\n"), 0644)
+		require.Nil(t, err, "could not write profile")
+
+		_, err = Get(file)
+		require.NotNil(t, err, "invalid profile was loaded")
+
+		_, err = Get("unknown")
+		require.NotNil(t, err, "unknown profile was loaded")
+	})
+}
+
+func TestUserAgentOverride(t *testing.T) {
+	profile, err := Get("desktop")
+	require.Nil(t, err, "could not get builtin profile")
+
+	override := profile.UserAgentOverride()
+	require.Equal(t, "en-US,en;q=0.9", override.AcceptLanguage)
+	require.Equal(t, "Windows", override.UserAgentMetadata.Platform)
+	require.Len(t, override.UserAgentMetadata.Brands, 3)
+	require.Equal(t, "124.0.6367.91", override.UserAgentMetadata.FullVersionList[1].Version)
+	require.Equal(t, "99", override.UserAgentMetadata.FullVersionList[2].Version)
+
+	iphone, err := Get("iphone")
+	require.Nil(t, err, "could not get builtin profile")
+	require.Nil(t, iphone.UserAgentOverride().UserAgentMetadata, "safari profile should not send client hints")
+}
+
+func TestStealthScript(t *testing.T) {
+	profile := &Profile{Locale: "de-DE", Platform: "Win32"}
+	require.Empty(t, profile.StealthScript(), "stealth script returned with stealth disabled")
+
+	profile.Stealth = true
+	script := profile.StealthScript()
+	require.Contains(t, script, `["de-DE","de"]`)
+	require.Contains(t, script, `"Win32"`)
+}
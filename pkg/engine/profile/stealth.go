@@ -0,0 +1,83 @@
+package profile
+
+import (
+	"fmt"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// stealthJS hides the browser properties revealing headless automation.
+// It receives the navigator languages and platform and the webgl vendor and renderer.
+const stealthJS = `((languages, platform, vendor, renderer) => {
+	const define = (obj, name, value) => {
+		try { Object.defineProperty(obj, name, {get: () => value, configurable: true}); } catch (e) {}
+	};
+	define(Navigator.prototype, "webdriver", undefined);
+	if (languages.length) define(Navigator.prototype, "languages", Object.freeze(languages));
+	if (platform) define(Navigator.prototype, "platform", platform);
+
+	// headless chrome has no plugins and mime types
+	const plugins = ["PDF Viewer", "Chrome PDF Viewer", "Chromium PDF Viewer", "Microsoft Edge PDF Viewer", "WebKit built-in PDF"].map(name => {
+		const plugin = Object.create(Plugin.prototype);
+		define(plugin, "name", name);
+		define(plugin, "filename", "internal-pdf-viewer");
+		define(plugin, "description", "Portable Document Format");
+		define(plugin, "length", 0);
+		return plugin;
+	});
+	const pluginArray = Object.create(PluginArray.prototype);
+	plugins.forEach((plugin, i) => define(pluginArray, i, plugin));
+	define(pluginArray, "length", plugins.length);
+	pluginArray.item = i => plugins[i] || null;
+	pluginArray.namedItem = name => plugins.find(p => p.name === name) || null;
+	pluginArray.refresh = () => {};
+	define(Navigator.prototype, "plugins", pluginArray);
+
+	if (!window.chrome) window.chrome = {};
+	if (!window.chrome.runtime) window.chrome.runtime = {};
+
+	// headless chrome reports denied notifications with a default permission
+	if (navigator.permissions && navigator.permissions.query) {
+		const query = navigator.permissions.query.bind(navigator.permissions);
+		navigator.permissions.query = parameters => parameters && parameters.name === "notifications"
+			? Promise.resolve({state: Notification.permission, onchange: null})
+			: query(parameters);
+	}
+
+	// UNMASKED_VENDOR_WEBGL and UNMASKED_RENDERER_WEBGL
+	const patchWebGL = proto => {
+		if (!proto || (!vendor && !renderer)) return;
+		const getParameter = proto.getParameter;
+		proto.getParameter = function (parameter) {
+			if (parameter === 37445 && vendor) return vendor;
+			if (parameter === 37446 && renderer) return renderer;
+			return getParameter.call(this, parameter);
+		};
+	};
+	patchWebGL(window.WebGLRenderingContext && WebGLRenderingContext.prototype);
+	patchWebGL(window.WebGL2RenderingContext && WebGL2RenderingContext.prototype);
+})(%s, %s, %s, %s)`
+
+// StealthScript returns the script evaluated on every new document to hide
+// the headless automation, it is empty if stealth is disabled for the profile.
+func (p *Profile) StealthScript() string {
+	if !p.Stealth {
+		return ""
+	}
+	var languages []string
+	for _, item := range strings.Split(p.AcceptLanguageHeader(), ",") {
+		language, _, _ := strings.Cut(item, ";")
+		if language = strings.TrimSpace(language); language != "" {
+			languages = append(languages, language)
+		}
+	}
+	if languages == nil {
+		languages = []string{}
+	}
+	marshal := func(value interface{}) string {
+		data, _ := jsoniter.MarshalToString(value)
+		return data
+	}
+	return fmt.Sprintf(stealthJS, marshal(languages), marshal(p.Platform), marshal(p.WebGLVendor), marshal(p.WebGLRenderer))
+}
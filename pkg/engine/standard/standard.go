@@ -1,8 +1,11 @@
 package standard
 
 import (
+	"time"
+
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/katana/pkg/engine/common"
+	"github.com/projectdiscovery/katana/pkg/engine/login"
 	"github.com/projectdiscovery/katana/pkg/types"
 	"github.com/projectdiscovery/utils/errkit"
 )
@@ -18,9 +21,26 @@ func New(options *types.CrawlerOptions) (*Crawler, error) {
 	if err != nil {
 		return nil, errkit.Wrap(err, "standard")
 	}
+	if err := shared.Authenticate(executeLogin(options)); err != nil {
+		return nil, errkit.Wrap(err, "standard")
+	}
 	return &Crawler{Shared: shared}, nil
 }
 
+// executeLogin runs the login flow in a short-lived browser, the session
+// cookies are then used by the http client through the shared cookie jar.
+func executeLogin(options *types.CrawlerOptions) common.LoginFunc {
+	return func(flow *login.Flow) (*login.Session, error) {
+		browser, release, err := login.LaunchBrowser(options.Options)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		return flow.Execute(browser, time.Duration(options.Options.Timeout)*time.Second)
+	}
+}
+
 // Close closes the crawler process
 func (c *Crawler) Close() error {
 	return nil
@@ -34,7 +54,7 @@ func (c *Crawler) Crawl(rootURL string) error {
 	}
 	defer crawlSession.CancelFunc()
 	gologger.Info().Msgf("Started standard crawling for => %v", rootURL)
-	if err := c.Do(crawlSession, c.makeRequest); err != nil {
+	if err := c.Do(crawlSession, c.MakeRequest); err != nil {
 		return errkit.Wrap(err, "standard")
 	}
 	return nil
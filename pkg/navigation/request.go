@@ -22,6 +22,33 @@ type Request struct {
 	Source         string              `json:"source,omitempty"`
 	CustomFields   map[string][]string `json:"custom_fields,omitempty"`
 	Raw            string              `json:"raw,omitempty"`
+	// Link is the relation metadata of link tags, anchors and link headers
+	Link *Link `json:"link,omitempty"`
+	// SkipRequest outputs the request without performing it, as for
+	// operations which could change the state of the target.
+	SkipRequest bool `json:"-"`
+}
+
+// Link is the relation metadata of a link
+type Link struct {
+	Rel      string `json:"rel,omitempty"`
+	Hreflang string `json:"hreflang,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Media    string `json:"media,omitempty"`
+}
+
+// NewLink returns the link metadata, or nil if it is empty
+func NewLink(rel, hreflang, linkType, media string) *Link {
+	link := &Link{
+		Rel:      strings.ToLower(strings.Join(strings.Fields(rel), " ")),
+		Hreflang: strings.TrimSpace(hreflang),
+		Type:     strings.TrimSpace(linkType),
+		Media:    strings.TrimSpace(media),
+	}
+	if *link == (Link{}) {
+		return nil
+	}
+	return link
 }
 
 // RequestURL returns the request URL for the navigation
@@ -36,8 +63,27 @@ func (n *Request) RequestURL() string {
 		builder.WriteString(n.Body)
 		builtURL := builder.String()
 		return builtURL
+	case "":
+		return ""
+	}
+	// other methods are prefixed so that they are not mixed up with post requests
+	builder := &strings.Builder{}
+	builder.WriteString(n.Method)
+	builder.WriteString(" ")
+	builder.WriteString(n.URL)
+	builder.WriteString(":")
+	builder.WriteString(n.Body)
+	return builder.String()
+}
+
+// IsSafeMethod returns true if the method does not change the state of the
+// target, i.e. it can be requested while crawling.
+func IsSafeMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
 	}
-	return ""
+	return false
 }
 
 // newNavigationRequestURL generates a navigation request from a relative URL
@@ -0,0 +1,40 @@
+package navigation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestURL(t *testing.T) {
+	tests := []struct {
+		request  Request
+		expected string
+	}{
+		{Request{Method: "GET", URL: "https://example.com/items"}, "https://example.com/items"},
+		{Request{Method: "POST", URL: "https://example.com/items", Body: "a=1"}, "https://example.com/items:a=1"},
+		{Request{URL: "https://example.com/items"}, ""},
+		{Request{Method: "PUT", URL: "https://example.com/items", Body: "a=1"}, "PUT https://example.com/items:a=1"},
+		{Request{Method: "DELETE", URL: "https://example.com/items/1"}, "DELETE https://example.com/items/1:"},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, test.request.RequestURL(), "could not get request url of %s %s", test.request.Method, test.request.URL)
+	}
+
+	// requests only differing by their method are distinct, they used to share
+	// the empty url and all but the first were dropped as duplicates
+	put := Request{Method: "PUT", URL: "https://example.com/items/1"}
+	deleted := Request{Method: "DELETE", URL: "https://example.com/items/1"}
+	post := Request{Method: "POST", URL: "https://example.com/items/1"}
+	require.NotEqual(t, put.RequestURL(), deleted.RequestURL())
+	require.NotEqual(t, put.RequestURL(), post.RequestURL())
+}
+
+func TestIsSafeMethod(t *testing.T) {
+	for _, method := range []string{"GET", "head", "OPTIONS"} {
+		require.True(t, IsSafeMethod(method), "could not detect safe method %s", method)
+	}
+	for _, method := range []string{"POST", "PUT", "patch", "DELETE", ""} {
+		require.False(t, IsSafeMethod(method), "could not detect unsafe method %s", method)
+	}
+}
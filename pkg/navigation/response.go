@@ -17,6 +17,49 @@ type Form struct {
 	Parameters []string `json:"parameters,omitempty"`
 }
 
+// ConsoleMessage is a message logged to the browser console
+type ConsoleMessage struct {
+	Level  string `json:"level,omitempty"`
+	Text   string `json:"text,omitempty"`
+	Source string `json:"source,omitempty"`
+	URL    string `json:"url,omitempty"`
+	Line   int    `json:"line,omitempty"`
+}
+
+// JSException is an uncaught javascript exception thrown in the page
+type JSException struct {
+	Message    string   `json:"message,omitempty"`
+	URL        string   `json:"url,omitempty"`
+	Line       int      `json:"line,omitempty"`
+	Column     int      `json:"column,omitempty"`
+	StackTrace []string `json:"stack_trace,omitempty"`
+}
+
+// FailedResource is a page resource which could not be loaded
+type FailedResource struct {
+	URL   string `json:"url,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// WebStorage is the browser storage of the page origin
+type WebStorage struct {
+	Origin         string            `json:"origin,omitempty"`
+	LocalStorage   map[string]string `json:"local_storage,omitempty"`
+	SessionStorage map[string]string `json:"session_storage,omitempty"`
+	IndexedDB      []string          `json:"indexed_db,omitempty"`
+	Cookies        []Cookie          `json:"cookies,omitempty"`
+}
+
+// Cookie is a cookie set by javascript in the browser
+type Cookie struct {
+	Name     string `json:"name,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+	SameSite string `json:"same_site,omitempty"`
+}
+
 func (h *Headers) MarshalJSON() ([]byte, error) {
 	hCopy := make(Headers)
 	for k, v := range *h {
@@ -40,7 +83,14 @@ type Response struct {
 	Raw                string            `json:"raw,omitempty"`
 	Forms              []Form            `json:"forms,omitempty"`
 	XhrRequests        []Request         `json:"xhr_requests,omitempty"`
+	ScriptData         []interface{}     `json:"script_data,omitempty"`
+	ConsoleMessages    []ConsoleMessage  `json:"console_messages,omitempty"`
+	JSExceptions       []JSException     `json:"js_exceptions,omitempty"`
+	FailedResources    []FailedResource  `json:"failed_resources,omitempty"`
+	Storage            *WebStorage       `json:"storage,omitempty"`
 	StoredResponsePath string            `json:"stored_response_path,omitempty"`
+	// Truncated is true if the body exceeds its size limit and was not read whole
+	Truncated bool `json:"-"`
 }
 
 func (n Response) AbsoluteURL(path string) string {
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/katana/pkg/navigation"
 	"github.com/projectdiscovery/utils/errkit"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 	urlutil "github.com/projectdiscovery/utils/url"
@@ -30,6 +31,10 @@ var FieldNames = []string{
 	"kv",
 	"dir",
 	"udir",
+	"rel",
+	"hreflang",
+	"mime",
+	"media",
 }
 
 type fieldOutput struct {
@@ -188,6 +193,10 @@ func formatField(output *Result, fields string) []fieldOutput {
 					svalue = append(svalue, fieldOutput{field: "dir", value: directory})
 				}
 			}
+		case "rel", "hreflang", "mime", "media":
+			if value := getValueForLinkField(output.Request.Link, f); value != "" {
+				svalue = append(svalue, fieldOutput{field: f, value: value})
+			}
 		default:
 			if v, ok := output.Request.CustomFields[f]; ok {
 				for _, r := range v {
@@ -258,6 +267,26 @@ func getValueForField(output *Result, parsed *url.URL, hostname, rdn, rurl, fiel
 			}
 		}
 		return strings.Join(values, "\n")
+	case "rel", "hreflang", "mime", "media":
+		return getValueForLinkField(output.Request.Link, field)
+	}
+	return ""
+}
+
+// getValueForLinkField returns the value of a link relation metadata field
+func getValueForLinkField(link *navigation.Link, field string) string {
+	if link == nil {
+		return ""
+	}
+	switch field {
+	case "rel":
+		return link.Rel
+	case "hreflang":
+		return link.Hreflang
+	case "mime":
+		return link.Type
+	case "media":
+		return link.Media
 	}
 	return ""
 }
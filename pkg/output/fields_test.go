@@ -42,4 +42,8 @@ func TestFormatField(t *testing.T) {
 		result := formatField(&Result{Request: &navigation.Request{URL: test.url}}, test.fields)
 		require.ElementsMatch(t, test.result, result, "could not equal value")
 	}
+
+	link := &Result{Request: &navigation.Request{URL: url, Link: navigation.NewLink("Alternate", "en-IN", "text/html", "only screen and (max-width: 640px)")}}
+	require.Equal(t, []fieldOutput{{"rel", "alternate"}, {"hreflang", "en-IN"}, {"mime", "text/html"}, {"media", "only screen and (max-width: 640px)"}}, formatField(link, "rel,hreflang,mime,media"))
+	require.Empty(t, formatField(&Result{Request: &navigation.Request{URL: url}}, "rel,hreflang"))
 }
@@ -53,6 +53,7 @@ type StandardWriter struct {
 	outputMutex           *sync.Mutex
 	storeResponse         bool
 	storeResponseDir      string
+	storeSourceMaps       bool
 	noClobber             bool
 	omitRaw               bool
 	omitBody              bool
@@ -76,6 +77,7 @@ func New(options Options) (Writer, error) {
 		outputMutex:           &sync.Mutex{},
 		storeResponse:         options.StoreResponse,
 		storeResponseDir:      options.StoreResponseDir,
+		storeSourceMaps:       options.StoreSourceMaps,
 		noClobber:             options.NoClobber,
 		omitRaw:               options.OmitRaw,
 		omitBody:              options.OmitBody,
@@ -202,6 +204,9 @@ func (w *StandardWriter) Write(result *Result) error {
 			}
 			_ = fileWriter.Close()
 		}
+		if w.storeSourceMaps {
+			storeSourceMapFiles(w.storeResponseDir, result)
+		}
 	}
 
 	if w.omitRaw {
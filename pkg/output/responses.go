@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/katana/pkg/utils"
 	"github.com/projectdiscovery/utils/errkit"
 	urlutil "github.com/projectdiscovery/utils/url"
 )
@@ -98,3 +99,35 @@ func updateIndex(storeResponseFolder string, result *Result) error {
 
 	return nil
 }
+
+// storeSourceMapFiles stores the original sources embedded in a source map
+// response to the sourcemaps directory of its host.
+func storeSourceMapFiles(storeResponseFolder string, result *Result) {
+	URL := result.Response.Resp.Request.URL
+	if !strings.HasSuffix(strings.ToLower(URL.Path), ".map") && !strings.Contains(result.Response.Resp.Header.Get("Content-Type"), "json") {
+		return
+	}
+	sourceMap, err := utils.ParseSourceMap(result.Response.Body)
+	if err != nil {
+		return
+	}
+	domain, err := getResponseHost(URL.String())
+	if err != nil {
+		return
+	}
+	folder := filepath.Join(createHostDir(storeResponseFolder, domain), "sourcemaps", getResponseHash(URL.String()))
+	for _, file := range sourceMap.Files() {
+		localPath := file.LocalPath()
+		if file.Content == "" || localPath == "" {
+			continue
+		}
+		fileName := filepath.Join(folder, filepath.FromSlash(localPath))
+		if err := os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+			gologger.Warning().Msgf("Could not create source map directory: %v\n", err)
+			return
+		}
+		if err := os.WriteFile(fileName, []byte(file.Content), 0644); err != nil {
+			gologger.Warning().Msgf("Could not store source map file %s: %v\n", localPath, err)
+		}
+	}
+}
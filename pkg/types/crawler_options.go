@@ -8,6 +8,7 @@ import (
 	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/katana/pkg/engine/parser"
 	"github.com/projectdiscovery/katana/pkg/output"
+	"github.com/projectdiscovery/katana/pkg/utils"
 	"github.com/projectdiscovery/katana/pkg/utils/extensions"
 	"github.com/projectdiscovery/katana/pkg/utils/filters"
 	"github.com/projectdiscovery/katana/pkg/utils/scope"
@@ -44,12 +45,27 @@ type CrawlerOptions struct {
 func NewCrawlerOptions(options *Options) (*CrawlerOptions, error) {
 	options.ConfigureOutput()
 	extensionsValidator := extensions.NewValidator(options.ExtensionsMatch, options.ExtensionFilter, options.NoDefaultExtFilter)
+	if options.DocumentCrawl {
+		// documents are denied by the default extension filter
+		extensionsValidator.AllowDefault(utils.DocumentExtensions...)
+	}
+	if options.SourceMaps {
+		extensionsValidator.AllowDefault(".map")
+	}
 
 	parserOptions := &parser.Options{
-		AutomaticFormFill:      options.AutomaticFormFill,
+		// forms are filled and submitted inside the browser in headless mode
+		AutomaticFormFill:      options.AutomaticFormFill && !options.Headless,
 		ScrapeJSLuiceResponses: options.ScrapeJSLuiceResponses,
 		ScrapeJSResponses:      options.ScrapeJSResponses,
 		DisableRedirects:       options.DisableRedirects,
+		Parsers:                options.Parsers,
+		DisabledParsers:        options.DisabledParsers,
+		DocumentCrawl:          options.DocumentCrawl,
+		SourceMaps:             options.SourceMaps,
+		OpenAPI:                options.OpenAPI,
+		GraphQL:                options.GraphQL,
+		GraphQLQueries:         options.GraphQLQueries,
 	}
 
 	responseParser := parser.NewResponseParser()
@@ -82,6 +98,7 @@ func NewCrawlerOptions(options *Options) (*CrawlerOptions, error) {
 		Fields:                options.Fields,
 		StoreFields:           options.StoreFields,
 		StoreResponseDir:      options.StoreResponseDir,
+		StoreSourceMaps:       options.StoreSourceMaps,
 		NoClobber:             options.NoClobber,
 		StoreFieldDir:         options.StoreFieldDir,
 		OmitRaw:               options.OmitRaw,
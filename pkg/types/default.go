@@ -17,5 +17,11 @@ func init() {
 		Concurrency: 10,
 		Parallelism: 10,
 		RateLimit:   150,
+
+		DocumentMaxSize: 10 * 1024 * 1024, // 10MB
+
+		HeadlessInstances:   1,
+		HeadlessMaxRestarts: 3,
+		HeadlessDialog:      "accept",
 	}
 }
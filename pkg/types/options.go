@@ -104,6 +104,22 @@ type Options struct {
 	ScrapeJSResponses bool
 	// ScrapeJSLuiceResponses enables scraping of endpoints from javascript using jsluice
 	ScrapeJSLuiceResponses bool
+	// OpenAPI enables the expansion of OpenAPI and Swagger specifications into api requests
+	OpenAPI bool
+	// GraphQL enables the detection of graphql endpoints and the enumeration of their operations
+	GraphQL bool
+	// GraphQLQueries enables requesting the read-only queries of the detected graphql endpoints
+	GraphQLQueries bool
+	// SourceMaps enables following and parsing the source maps of javascript and css files
+	SourceMaps bool
+	// DocumentCrawl enables fetching pdf and office documents to parse their links and metadata
+	DocumentCrawl bool
+	// DocumentMaxSize is the maximum size of a document to read for parsing
+	DocumentMaxSize int
+	// Parsers are the names of the only response parsers to run
+	Parsers goflags.StringSlice
+	// DisabledParsers are the names of the response parsers not to run
+	DisabledParsers goflags.StringSlice
 	// CustomHeaders is a list of custom headers to add to request
 	CustomHeaders goflags.StringSlice
 	// Headless enables headless scraping
@@ -122,8 +138,13 @@ type Options struct {
 	HeadlessNoSandbox bool
 	// SystemChromePath : Specify the chrome binary path for headless crawling
 	SystemChromePath string
-	// ChromeWSUrl : Specify the Chrome debugger websocket url for a running Chrome instance to attach to
+	// ChromeWSUrl : Specify the Chrome debugger websocket url for a running Chrome instance to attach to,
+	// multiple comma separated urls can be specified to use a pool of browsers
 	ChromeWSUrl string
+	// HeadlessInstances is the number of local chrome instances used for headless crawling
+	HeadlessInstances int
+	// HeadlessInstanceConcurrency is the maximum number of concurrent pages per chrome instance
+	HeadlessInstanceConcurrency int
 	// OnResult allows callback function on a result
 	OnResult OnResultCallback
 	// OnSkipURL allows callback function on a skipped url
@@ -132,6 +153,8 @@ type Options struct {
 	StoreResponse bool
 	// StoreResponseDir specifies if katana should use a custom directory to store http requests/responses
 	StoreResponseDir string
+	// StoreSourceMaps specifies if katana should store the original sources of source maps in the response directory
+	StoreSourceMaps bool
 	// NoClobber specifies if katana should overwrite existing output files
 	NoClobber bool
 	// StoreFieldDir specifies if katana should use a custom directory to store fields
@@ -146,6 +169,48 @@ type Options struct {
 	HeadlessNoIncognito bool
 	// XhrExtraction extract xhr requests
 	XhrExtraction bool
+	// XhrReplay enqueues the xhr requests captured in headless mode to be crawled
+	XhrReplay bool
+	// XhrReplayUnsafe also replays the captured xhr requests whose method can change
+	// the state of the target, e.g. post, put or delete requests
+	XhrReplayUnsafe bool
+	// LoginFlow is the path to a yaml login flow executed in the browser before crawling
+	LoginFlow string
+	// HeadlessInitScripts is a list of javascript files evaluated on every page before page scripts
+	HeadlessInitScripts goflags.StringSlice
+	// HeadlessBlockResources is a list of resource types blocked in headless mode
+	HeadlessBlockResources goflags.StringSlice
+	// HeadlessBlockURLs is a list of url regexes blocked in headless mode
+	HeadlessBlockURLs goflags.StringSlice
+	// HeadlessBlockThirdParty blocks the requests to other registrable domains than the page in headless mode
+	HeadlessBlockThirdParty bool
+	// HeadlessRecordBlocked writes blocked requests to the output as discovered urls
+	HeadlessRecordBlocked bool
+	// ConsoleCapture collects browser console messages, javascript exceptions and failed resource loads
+	ConsoleCapture bool
+	// StorageExtraction extracts web storage and javascript set cookies in headless mode
+	StorageExtraction bool
+	// HeadlessMaxRestarts is the maximum number of browser restarts after a crash or disconnection
+	HeadlessMaxRestarts int
+	// HeadlessProfile is the name or yaml file of the device, locale and stealth profile used in headless mode
+	HeadlessProfile string
+	// HeadlessWait is the strategy used to wait for pages to be ready in headless mode
+	HeadlessWait string
+	// HeadlessWaitRules are per url regex wait strategies in the form <url-regex> => <strategy>
+	HeadlessWaitRules goflags.StringSlice
+	// HeadlessDialog is the response to javascript dialogs (accept, dismiss or accept:<prompt text>)
+	HeadlessDialog string
+	// HeadlessConsent clicks the accept button of consent managers in headless mode
+	HeadlessConsent bool
+	// HeadlessConsentSelectors is a file of css selectors replacing the default consent buttons
+	HeadlessConsentSelectors string
+	// HeadlessScroll is the maximum number of times a page is scrolled to load more content
+	HeadlessScroll int
+	// HeadlessOnly renders all the requests in the browser instead of
+	// fetching the static resources with the http client in headless mode
+	HeadlessOnly bool
+	// HeadlessRoutes are the '<url-regex> => headless|standard' rules selecting the engine of the urls
+	HeadlessRoutes goflags.StringSlice
 	// HealthCheck determines if a self-healthcheck should be performed
 	HealthCheck bool
 	// PprofServer enables pprof server
@@ -215,6 +280,17 @@ func (options *Options) ParseHeadlessOptionalArguments() map[string]string {
 	return optionalArguments
 }
 
+// ParseChromeWSUrls returns the comma separated chrome debugger websocket urls
+func (options *Options) ParseChromeWSUrls() []string {
+	var urls []string
+	for _, value := range strings.Split(options.ChromeWSUrl, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			urls = append(urls, value)
+		}
+	}
+	return urls
+}
+
 func (options *Options) ShouldResume() bool {
 	return options.Resume != "" && fileutil.FileExists(options.Resume)
 }
@@ -114,3 +114,11 @@ func TestParseHeadlessOptionalArguments(t *testing.T) {
 		})
 	}
 }
+
+func TestParseChromeWSUrls(t *testing.T) {
+	opt := Options{ChromeWSUrl: "ws://127.0.0.1:9222/devtools/browser/a, ws://127.0.0.1:9223/devtools/browser/b,"}
+	require.Equal(t, []string{"ws://127.0.0.1:9222/devtools/browser/a", "ws://127.0.0.1:9223/devtools/browser/b"}, opt.ParseChromeWSUrls())
+
+	opt = Options{}
+	require.Empty(t, opt.ParseChromeWSUrls())
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// BodyShape returns the structure of a request body without its values so that
+// requests differing only by their values can be deduplicated. JSON bodies are
+// reduced to their keys and value types, form bodies to their sorted keys and
+// other bodies are returned as is.
+func BodyShape(body string) string {
+	trimmed := strings.TrimSpace(body)
+	if trimmed == "" {
+		return ""
+	}
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		var value interface{}
+		if err := jsoniter.UnmarshalFromString(trimmed, &value); err == nil {
+			builder := &strings.Builder{}
+			writeJSONShape(builder, value)
+			return builder.String()
+		}
+	}
+	if values, err := url.ParseQuery(trimmed); err == nil && strings.Contains(trimmed, "=") {
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return strings.Join(keys, "&")
+	}
+	return body
+}
+
+func writeJSONShape(builder *strings.Builder, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		builder.WriteRune('{')
+		for i, key := range keys {
+			if i > 0 {
+				builder.WriteRune(',')
+			}
+			builder.WriteString(key)
+			builder.WriteRune(':')
+			writeJSONShape(builder, v[key])
+		}
+		builder.WriteRune('}')
+	case []interface{}:
+		// arrays are assumed to be homogeneous, the first item describes them
+		builder.WriteRune('[')
+		if len(v) > 0 {
+			writeJSONShape(builder, v[0])
+		}
+		builder.WriteRune(']')
+	case string:
+		builder.WriteString("string")
+	case float64:
+		builder.WriteString("number")
+	case bool:
+		builder.WriteString("bool")
+	default:
+		builder.WriteString("null")
+	}
+}
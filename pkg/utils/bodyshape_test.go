@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyShape(t *testing.T) {
+	require.Equal(t,
+		BodyShape(`{"user":{"id":1,"name":"a"},"tags":["x","y"],"admin":false}`),
+		BodyShape(`{"admin":true,"tags":["z"],"user":{"name":"b","id":2}}`),
+		"json bodies with the same structure have different shapes")
+	require.Equal(t, `{admin:bool,tags:[string],user:{id:number,name:string}}`, BodyShape(`{"user":{"id":1,"name":"a"},"tags":["x"],"admin":false}`))
+	require.NotEqual(t, BodyShape(`{"id":1}`), BodyShape(`{"id":"1"}`), "json bodies with different types have the same shape")
+
+	require.Equal(t, "a&b", BodyShape("b=2&a=1"))
+	require.Equal(t, "plain text", BodyShape("plain text"))
+	require.Equal(t, "", BodyShape("  "))
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"mime"
+	"path"
+	"strings"
+)
+
+// DocumentExtensions are the extensions of the documents whose links and
+// metadata can be parsed.
+var DocumentExtensions = []string{".pdf", ".docx", ".xlsx", ".pptx"}
+
+// documentMediaTypes are the media types of the parsed documents
+var documentMediaTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   ".docx",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         ".xlsx",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": ".pptx",
+}
+
+// DocumentType returns the extension of the document type of a response from
+// its content type or url path, or an empty string if it is not a document.
+func DocumentType(contentType, urlPath string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if extension, ok := documentMediaTypes[mediaType]; ok {
+		return extension
+	}
+	// documents are often served as a generic binary content type
+	if mediaType != "" && mediaType != "application/octet-stream" && mediaType != "binary/octet-stream" && mediaType != "application/download" {
+		return ""
+	}
+	extension := strings.ToLower(path.Ext(urlPath))
+	for _, item := range DocumentExtensions {
+		if extension == item {
+			return extension
+		}
+	}
+	return ""
+}
@@ -15,6 +15,8 @@ var defaultExtFilter = []string{".3g2", ".3gp", ".7z", ".apk", ".arj", ".avi", "
 type Validator struct {
 	extensionsMatch  map[string]struct{}
 	extensionsFilter map[string]struct{}
+	// userFilter are the extensions filtered by the user
+	userFilter map[string]struct{}
 }
 
 // NewValidator creates a new extension validator instance
@@ -22,6 +24,7 @@ func NewValidator(extensionsMatch, extensionsFilter []string, noDefaultExtFilter
 	validator := &Validator{
 		extensionsMatch:  make(map[string]struct{}),
 		extensionsFilter: make(map[string]struct{}),
+		userFilter:       make(map[string]struct{}),
 	}
 
 	for _, extension := range extensionsMatch {
@@ -34,10 +37,22 @@ func NewValidator(extensionsMatch, extensionsFilter []string, noDefaultExtFilter
 	}
 	for _, extension := range extensionsFilter {
 		validator.extensionsFilter[normalizeExtension(extension)] = struct{}{}
+		validator.userFilter[normalizeExtension(extension)] = struct{}{}
 	}
 	return validator
 }
 
+// AllowDefault removes the extensions from the default filter, the
+// extensions filtered by the user are still denied.
+func (e *Validator) AllowDefault(extensions ...string) {
+	for _, extension := range extensions {
+		extension = normalizeExtension(extension)
+		if _, ok := e.userFilter[extension]; !ok {
+			delete(e.extensionsFilter, extension)
+		}
+	}
+}
+
 // ValidatePath returns true if an extension is allowed by the validator
 func (e *Validator) ValidatePath(item string) bool {
 	var extension string
@@ -23,4 +23,9 @@ func TestValidatorValidate(t *testing.T) {
 
 	validator = NewValidator(nil, []string{"png"}, true)
 	require.False(t, validator.ValidatePath("main.png"), "could not validate correct data with no default extension filter and custom filter")
+
+	validator = NewValidator(nil, []string{"xlsx"}, false)
+	validator.AllowDefault(".pdf", ".xlsx")
+	require.True(t, validator.ValidatePath("report.pdf"), "could not validate correct data with allowed default extension")
+	require.False(t, validator.ValidatePath("report.xlsx"), "could not validate correct data with allowed extension filtered by user")
 }
@@ -0,0 +1,351 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/utils/errkit"
+)
+
+// GraphQLIntrospectionQuery is the introspection query of the root operation
+// types, with the fields, enum values and input fields needed to build calls.
+const GraphQLIntrospectionQuery = `query IntrospectionQuery { __schema { queryType { name } mutationType { name } types { kind name fields(includeDeprecated: true) { name args { name type { ...TypeRef } defaultValue } type { ...TypeRef } } inputFields { name type { ...TypeRef } defaultValue } enumValues(includeDeprecated: true) { name } } } } fragment TypeRef on __Type { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } }`
+
+const (
+	// graphQLQueryMarker and graphQLMutationMarker are field names no schema is
+	// expected to define, their validation errors identify the root types.
+	graphQLQueryMarker    = "katanaQueryProbe"
+	graphQLMutationMarker = "katanaMutationProbe"
+	// maxGraphQLInputDepth is the maximum depth of generated input objects
+	maxGraphQLInputDepth = 3
+)
+
+// graphQLSuggestionWords are misspelled names of common operations, the field
+// suggestions of their validation errors reveal the root fields close to them.
+var graphQLSuggestionWords = []string{
+	"usr", "usrs", "acount", "profil", "ordr", "prodct", "itm", "sarch", "nod", "lst",
+	"logn", "logot", "regster", "sesion", "tokn", "pasword", "creat", "updat", "delet", "remov",
+	"setings", "confg", "mesage", "coment", "pst", "fil", "uplod", "paymnt", "invoic", "admn",
+}
+
+var (
+	// graphQLSuggestionRegex matches the field suggestions of validation errors
+	graphQLSuggestionRegex = regexp.MustCompile(`Cannot query field "([^"]+)" on type "([^"]+)"\.\s*Did you mean (.+?)\?`)
+	// graphQLQuotedRegex matches the quoted names of a suggestion list
+	graphQLQuotedRegex = regexp.MustCompile(`"([_A-Za-z][_0-9A-Za-z]*)"`)
+	// graphQLFieldErrorRegex matches the type of unknown field validation errors
+	graphQLFieldErrorRegex = regexp.MustCompile(`Cannot query field "([^"]+)" on type "([^"]+)"`)
+)
+
+// GraphQLOperation is a query or mutation exposed by a graphql endpoint
+type GraphQLOperation struct {
+	// Type is the operation type, query or mutation
+	Type string
+	// Name is the name of the root field
+	Name string
+	// Arguments are the arguments of the root field with their types
+	Arguments []string
+	// Query is a document calling the root field with example arguments
+	Query string
+}
+
+// GraphQLSchema is a graphql schema returned by an introspection query
+type GraphQLSchema struct {
+	QueryType    string
+	MutationType string
+	types        map[string]graphQLType
+}
+
+type graphQLResponse struct {
+	Data struct {
+		Schema *struct {
+			QueryType    *graphQLTypeName `json:"queryType"`
+			MutationType *graphQLTypeName `json:"mutationType"`
+			Types        []graphQLType    `json:"types"`
+		} `json:"__schema"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type graphQLTypeName struct {
+	Name string `json:"name"`
+}
+
+type graphQLType struct {
+	Kind        string              `json:"kind"`
+	Name        string              `json:"name"`
+	Fields      []graphQLField      `json:"fields"`
+	InputFields []graphQLInputValue `json:"inputFields"`
+	EnumValues  []graphQLTypeName   `json:"enumValues"`
+}
+
+type graphQLField struct {
+	Name string              `json:"name"`
+	Args []graphQLInputValue `json:"args"`
+	Type graphQLTypeRef      `json:"type"`
+}
+
+type graphQLInputValue struct {
+	Name         string         `json:"name"`
+	Type         graphQLTypeRef `json:"type"`
+	DefaultValue *string        `json:"defaultValue"`
+}
+
+type graphQLTypeRef struct {
+	Kind   string          `json:"kind"`
+	Name   string          `json:"name"`
+	OfType *graphQLTypeRef `json:"ofType"`
+}
+
+// String returns the type reference in graphql notation, e.g. [ID!]!
+func (t graphQLTypeRef) String() string {
+	switch {
+	case t.Kind == "NON_NULL" && t.OfType != nil:
+		return t.OfType.String() + "!"
+	case t.Kind == "LIST" && t.OfType != nil:
+		return "[" + t.OfType.String() + "]"
+	}
+	return t.Name
+}
+
+// named returns the named type wrapped by the list and non null types
+func (t graphQLTypeRef) named() graphQLTypeRef {
+	for t.OfType != nil && (t.Kind == "NON_NULL" || t.Kind == "LIST") {
+		t = *t.OfType
+	}
+	return t
+}
+
+// ParseGraphQLSchema parses the schema of an introspection query response
+func ParseGraphQLSchema(data string) (*GraphQLSchema, error) {
+	response := &graphQLResponse{}
+	if err := jsoniter.UnmarshalFromString(data, response); err != nil {
+		return nil, errkit.Wrap(err, "graphql: could not unmarshal response")
+	}
+	if response.Data.Schema == nil || len(response.Data.Schema.Types) == 0 {
+		return nil, errkit.New("graphql: not an introspection response")
+	}
+	schema := &GraphQLSchema{types: make(map[string]graphQLType)}
+	if response.Data.Schema.QueryType != nil {
+		schema.QueryType = response.Data.Schema.QueryType.Name
+	}
+	if response.Data.Schema.MutationType != nil {
+		schema.MutationType = response.Data.Schema.MutationType.Name
+	}
+	for _, item := range response.Data.Schema.Types {
+		schema.types[item.Name] = item
+	}
+	return schema, nil
+}
+
+// Operations returns the queries and mutations of the schema sorted by name
+func (s *GraphQLSchema) Operations() []GraphQLOperation {
+	var operations []GraphQLOperation
+	for _, root := range []struct{ operationType, typeName string }{{"query", s.QueryType}, {"mutation", s.MutationType}} {
+		rootType, ok := s.types[root.typeName]
+		if root.typeName == "" || !ok {
+			continue
+		}
+		fields := append([]graphQLField(nil), rootType.Fields...)
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+
+		for _, field := range fields {
+			operation := GraphQLOperation{Type: root.operationType, Name: field.Name}
+			var arguments []string
+			for _, arg := range field.Args {
+				operation.Arguments = append(operation.Arguments, arg.Name+": "+arg.Type.String())
+				// only the required arguments are given a value
+				if arg.Type.Kind == "NON_NULL" && arg.DefaultValue == nil {
+					arguments = append(arguments, arg.Name+": "+s.exampleValue(arg.Type, 0))
+				}
+			}
+			operation.Query = graphQLDocument(root.operationType, field.Name, arguments, s.selection(field.Type))
+			operations = append(operations, operation)
+		}
+	}
+	return operations
+}
+
+// selection returns the selection set of a field type, its scalar fields
+// without required arguments or the typename of composite types.
+func (s *GraphQLSchema) selection(typeRef graphQLTypeRef) string {
+	named := typeRef.named()
+	switch named.Kind {
+	case "SCALAR", "ENUM":
+		return ""
+	case "OBJECT", "INTERFACE":
+		var fields []string
+		for _, field := range s.types[named.Name].Fields {
+			kind := field.Type.named().Kind
+			if (kind != "SCALAR" && kind != "ENUM") || hasRequiredArgument(field.Args) {
+				continue
+			}
+			fields = append(fields, field.Name)
+		}
+		if len(fields) > 0 {
+			return "{ " + strings.Join(fields, " ") + " }"
+		}
+	}
+	return "{ __typename }"
+}
+
+// hasRequiredArgument returns true if one of the arguments is required
+func hasRequiredArgument(args []graphQLInputValue) bool {
+	for _, arg := range args {
+		if arg.Type.Kind == "NON_NULL" && arg.DefaultValue == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// exampleValue returns an example graphql literal for an input type
+func (s *GraphQLSchema) exampleValue(typeRef graphQLTypeRef, depth int) string {
+	switch {
+	case typeRef.Kind == "NON_NULL" && typeRef.OfType != nil:
+		return s.exampleValue(*typeRef.OfType, depth)
+	case typeRef.Kind == "LIST" && typeRef.OfType != nil:
+		return "[" + s.exampleValue(*typeRef.OfType, depth) + "]"
+	}
+
+	switch typeRef.Name {
+	case "Int":
+		return "1"
+	case "Float":
+		return "1.5"
+	case "Boolean":
+		return "true"
+	case "ID":
+		return `"1"`
+	}
+	inputType := s.types[typeRef.Name]
+	switch inputType.Kind {
+	case "ENUM":
+		if len(inputType.EnumValues) > 0 {
+			return inputType.EnumValues[0].Name
+		}
+	case "INPUT_OBJECT":
+		var values []string
+		if depth < maxGraphQLInputDepth {
+			for _, field := range inputType.InputFields {
+				if field.Type.Kind == "NON_NULL" && field.DefaultValue == nil {
+					values = append(values, field.Name+": "+s.exampleValue(field.Type, depth+1))
+				}
+			}
+		}
+		return "{" + strings.Join(values, ", ") + "}"
+	}
+	return strconv.Quote(FormData.Placeholder)
+}
+
+// graphQLDocument returns an operation document calling a root field
+func graphQLDocument(operationType, field string, arguments []string, selection string) string {
+	builder := &strings.Builder{}
+	builder.WriteString(operationType)
+	builder.WriteString(" { ")
+	builder.WriteString(field)
+	if len(arguments) > 0 {
+		builder.WriteString("(")
+		builder.WriteString(strings.Join(arguments, ", "))
+		builder.WriteString(")")
+	}
+	if selection != "" {
+		builder.WriteString(" ")
+		builder.WriteString(selection)
+	}
+	builder.WriteString(" }")
+	return builder.String()
+}
+
+// IsGraphQLIntrospectionDisabled returns true if the response is an error
+// response rejecting an introspection query.
+func IsGraphQLIntrospectionDisabled(data string) bool {
+	response := &graphQLResponse{}
+	if err := jsoniter.UnmarshalFromString(data, response); err != nil || response.Data.Schema != nil {
+		return false
+	}
+	for _, item := range response.Errors {
+		message := strings.ToLower(item.Message)
+		if strings.Contains(message, "introspection") || strings.Contains(message, "__schema") {
+			return true
+		}
+	}
+	return false
+}
+
+// GraphQLSuggestionProbes returns the query and mutation documents probing the
+// field suggestions of an endpoint. Each of them selects unknown fields only,
+// so that they are rejected by the validation without being executed.
+func GraphQLSuggestionProbes() []string {
+	fields := strings.Join(graphQLSuggestionWords, " ")
+	return []string{
+		"query { " + graphQLQueryMarker + " " + fields + " }",
+		"mutation { " + graphQLMutationMarker + " " + fields + " }",
+	}
+}
+
+// ParseGraphQLSuggestions returns the operations revealed by the field
+// suggestions of the validation errors of a suggestion probe response.
+func ParseGraphQLSuggestions(data string) []GraphQLOperation {
+	response := &graphQLResponse{}
+	if err := jsoniter.UnmarshalFromString(data, response); err != nil {
+		return nil
+	}
+
+	// the marker fields identify the root types of the probes
+	rootTypes := make(map[string]string)
+	for _, item := range response.Errors {
+		match := graphQLFieldErrorRegex.FindStringSubmatch(item.Message)
+		switch {
+		case match == nil:
+		case match[1] == graphQLQueryMarker:
+			rootTypes[match[2]] = "query"
+		case match[1] == graphQLMutationMarker:
+			rootTypes[match[2]] = "mutation"
+		}
+	}
+
+	seen := make(map[string]struct{})
+	var operations []GraphQLOperation
+	for _, item := range response.Errors {
+		match := graphQLSuggestionRegex.FindStringSubmatch(item.Message)
+		if match == nil {
+			continue
+		}
+		operationType, ok := rootTypes[match[2]]
+		if !ok {
+			continue
+		}
+		for _, name := range graphQLQuotedRegex.FindAllStringSubmatch(match[3], -1) {
+			key := operationType + " " + name[1]
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			operations = append(operations, GraphQLOperation{
+				Type: operationType,
+				Name: name[1],
+				// the type of suggested fields is unknown, most of them are objects
+				Query: graphQLDocument(operationType, name[1], nil, "{ __typename }"),
+			})
+		}
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		if operations[i].Type != operations[j].Type {
+			return operations[i].Type == "query"
+		}
+		return operations[i].Name < operations[j].Name
+	})
+	return operations
+}
+
+// GraphQLRequestBody returns the json body of a graphql request
+func GraphQLRequestBody(query string) string {
+	body, _ := jsoniter.MarshalToString(map[string]string{"query": query})
+	return body
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLOperations(t *testing.T) {
+	t.Run("introspection", func(t *testing.T) {
+		schema, err := ParseGraphQLSchema(`{"data": {"__schema": {
+	"queryType": {"name": "RootQuery"},
+	"mutationType": null,
+	"types": [
+		{"kind": "OBJECT", "name": "RootQuery", "fields": [
+			{"name": "search", "args": [
+				{"name": "terms", "type": {"kind": "NON_NULL", "ofType": {"kind": "LIST", "ofType": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "String"}}}}, "defaultValue": null},
+				{"name": "page", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "Int"}}, "defaultValue": "1"}
+			], "type": {"kind": "UNION", "name": "SearchResult"}},
+			{"name": "version", "args": [], "type": {"kind": "SCALAR", "name": "String"}}
+		]}
+	]
+}}}`)
+		require.NoError(t, err)
+		require.Equal(t, []GraphQLOperation{
+			{Type: "query", Name: "search", Arguments: []string{"terms: [String!]!", "page: Int!"}, Query: `query { search(terms: ["katana"]) { __typename } }`},
+			{Type: "query", Name: "version", Query: "query { version }"},
+		}, schema.Operations())
+
+		_, err = ParseGraphQLSchema(`{"data": {"__typename": "Query"}}`)
+		require.Error(t, err)
+	})
+
+	t.Run("introspection disabled", func(t *testing.T) {
+		require.True(t, IsGraphQLIntrospectionDisabled(`{"errors": [{"message": "Cannot query field \"__schema\" on type \"Query\"."}]}`))
+		require.False(t, IsGraphQLIntrospectionDisabled(`{"errors": [{"message": "Must provide query string."}]}`))
+	})
+
+	t.Run("suggestions", func(t *testing.T) {
+		operations := ParseGraphQLSuggestions(`{"errors": [
+	{"message": "Cannot query field \"katanaMutationProbe\" on type \"RootMutation\"."},
+	{"message": "Cannot query field \"updat\" on type \"RootMutation\". Did you mean \"update\", \"updateUser\", or \"updatePost\"?"},
+	{"message": "Cannot query field \"usr\" on type \"User\". Did you mean \"user\"?"}
+]}`)
+		require.Len(t, operations, 3)
+		require.Equal(t, GraphQLOperation{Type: "mutation", Name: "update", Query: "mutation { update { __typename } }"}, operations[0])
+		require.Equal(t, "updatePost", operations[1].Name)
+		require.Equal(t, "updateUser", operations[2].Name)
+	})
+}
@@ -0,0 +1,512 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/utils/errkit"
+	"gopkg.in/yaml.v3"
+)
+
+// maxSchemaDepth is the maximum depth of the schemas and references expanded into values
+const maxSchemaDepth = 8
+
+// openAPIMethods are the methods of the operations of a path item, in output order
+var openAPIMethods = []string{"get", "head", "options", "post", "put", "patch", "delete"}
+
+// openAPIPathParameterRegex matches the path template expressions
+var openAPIPathParameterRegex = regexp.MustCompile(`\{[^{}/]*\}`)
+
+// OpenAPISpec is a parsed OpenAPI 3 or Swagger 2 specification
+type OpenAPISpec struct {
+	document map[string]interface{}
+	// Swagger is true for Swagger 2 specifications
+	Swagger bool
+}
+
+// OpenAPIOperation is an api request expanded from an operation of a specification
+type OpenAPIOperation struct {
+	Method  string
+	URL     string
+	Body    string
+	Headers map[string]string
+	// Path is the path template of the operation, e.g. /users/{id}
+	Path string
+}
+
+// ParseOpenAPI parses a json or yaml OpenAPI 3 or Swagger 2 specification
+func ParseOpenAPI(data string) (*OpenAPISpec, error) {
+	data = strings.TrimSpace(data)
+	if !strings.Contains(data, "paths") || (!strings.Contains(data, "openapi") && !strings.Contains(data, "swagger")) {
+		return nil, errkit.New("openapi: not a specification")
+	}
+
+	var document map[string]interface{}
+	var err error
+	if strings.HasPrefix(data, "{") {
+		err = jsoniter.UnmarshalFromString(data, &document)
+	} else {
+		err = yaml.Unmarshal([]byte(data), &document)
+	}
+	if err != nil {
+		return nil, errkit.Wrap(err, "openapi: could not unmarshal specification")
+	}
+	if _, ok := document["paths"].(map[string]interface{}); !ok {
+		return nil, errkit.New("openapi: specification has no paths")
+	}
+
+	spec := &OpenAPISpec{document: document}
+	switch {
+	case strings.HasPrefix(fmt.Sprint(document["openapi"]), "3"):
+	case fmt.Sprint(document["swagger"]) == "2.0" || fmt.Sprint(document["swagger"]) == "2":
+		spec.Swagger = true
+	default:
+		return nil, errkit.New("openapi: unsupported specification version")
+	}
+	return spec, nil
+}
+
+// Operations expands the operations of the specification into requests with
+// example or schema derived values, relative servers are resolved against
+// the url of the specification.
+func (s *OpenAPISpec) Operations(specURL string) []OpenAPIOperation {
+	base, err := url.Parse(specURL)
+	if err != nil {
+		return nil
+	}
+	paths, _ := s.document["paths"].(map[string]interface{})
+
+	var operations []OpenAPIOperation
+	for _, path := range sortedKeys(paths) {
+		pathItem, ok := s.resolve(paths[path], 0).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, method := range openAPIMethods {
+			operation, ok := s.resolve(pathItem[method], 0).(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, server := range s.servers(base, pathItem, operation) {
+				operations = append(operations, s.expandOperation(strings.ToUpper(method), server, path, pathItem, operation))
+			}
+		}
+	}
+	return operations
+}
+
+// servers returns the base urls of an operation, operation and path servers
+// override the servers of the specification.
+func (s *OpenAPISpec) servers(base *url.URL, pathItem, operation map[string]interface{}) []string {
+	if s.Swagger {
+		scheme := base.Scheme
+		if schemes, ok := s.document["schemes"].([]interface{}); ok && len(schemes) > 0 {
+			scheme = fmt.Sprint(schemes[0])
+		}
+		host := base.Host
+		if value, ok := s.document["host"].(string); ok && value != "" {
+			host = value
+		}
+		basePath, _ := s.document["basePath"].(string)
+		return []string{fmt.Sprintf("%s://%s/%s", scheme, host, strings.Trim(basePath, "/"))}
+	}
+
+	var servers []interface{}
+	for _, object := range []map[string]interface{}{operation, pathItem, s.document} {
+		if items, ok := object["servers"].([]interface{}); ok && len(items) > 0 {
+			servers = items
+			break
+		}
+	}
+
+	var results []string
+	seen := make(map[string]struct{})
+	for _, item := range servers {
+		server, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value, _ := server["url"].(string)
+		variables, _ := server["variables"].(map[string]interface{})
+		value = openAPIPathParameterRegex.ReplaceAllStringFunc(value, func(expression string) string {
+			variable, _ := variables[strings.Trim(expression, "{}")].(map[string]interface{})
+			return fmt.Sprint(variable["default"])
+		})
+		resolved, err := base.Parse(value)
+		if err != nil {
+			continue
+		}
+		if _, ok := seen[resolved.String()]; ok {
+			continue
+		}
+		seen[resolved.String()] = struct{}{}
+		results = append(results, resolved.String())
+	}
+	if len(results) == 0 {
+		results = append(results, fmt.Sprintf("%s://%s/", base.Scheme, base.Host))
+	}
+	return results
+}
+
+// expandOperation expands an operation into a request for the server
+func (s *OpenAPISpec) expandOperation(method, server, path string, pathItem, operation map[string]interface{}) OpenAPIOperation {
+	result := OpenAPIOperation{Method: method, Path: path, Headers: make(map[string]string)}
+
+	query := url.Values{}
+	form := make(map[string]interface{})
+	var body interface{}
+	for _, parameter := range s.parameters(pathItem, operation) {
+		name, _ := parameter["name"].(string)
+		location, _ := parameter["in"].(string)
+		if location == "body" {
+			body = s.exampleValue(parameter["schema"], 0)
+			continue
+		}
+		value := s.parameterValue(parameter)
+		switch location {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(openAPIString(value)))
+		case "query":
+			if values, ok := value.([]interface{}); ok {
+				for _, item := range values {
+					query.Add(name, openAPIString(item))
+				}
+			} else {
+				query.Add(name, openAPIString(value))
+			}
+		case "header":
+			result.Headers[name] = openAPIString(value)
+		case "formData":
+			form[name] = value
+		}
+	}
+	// path expressions without parameters are filled with a default value
+	path = openAPIPathParameterRegex.ReplaceAllString(path, "1")
+
+	result.URL = strings.TrimSuffix(server, "/") + "/" + strings.TrimPrefix(path, "/")
+	if len(query) > 0 {
+		result.URL += "?" + query.Encode()
+	}
+
+	var contentType string
+	if s.Swagger {
+		consumes := openAPIStrings(operation["consumes"])
+		if len(consumes) == 0 {
+			consumes = openAPIStrings(s.document["consumes"])
+		}
+		switch {
+		case body != nil:
+			contentType = "application/json"
+		case len(form) > 0:
+			contentType = "application/x-www-form-urlencoded"
+			if len(consumes) > 0 && strings.Contains(consumes[0], "multipart/form-data") {
+				contentType = "multipart/form-data"
+			}
+			body = form
+		}
+	} else if requestBody, ok := s.resolve(operation["requestBody"], 0).(map[string]interface{}); ok {
+		contentType, body = s.requestBodyValue(requestBody)
+	}
+	if body != nil && contentType != "" {
+		result.Body, contentType = encodeOpenAPIBody(body, contentType)
+		result.Headers["Content-Type"] = contentType
+	}
+	if result.Body != "" && (method == http.MethodGet || method == http.MethodHead) {
+		result.Body = ""
+		delete(result.Headers, "Content-Type")
+	}
+	return result
+}
+
+// parameters returns the resolved parameters of an operation, operation
+// parameters override the path item ones with the same name and location.
+func (s *OpenAPISpec) parameters(pathItem, operation map[string]interface{}) []map[string]interface{} {
+	var parameters []map[string]interface{}
+	index := make(map[string]int)
+	for _, object := range []map[string]interface{}{pathItem, operation} {
+		items, _ := object["parameters"].([]interface{})
+		for _, item := range items {
+			parameter, ok := s.resolve(item, 0).(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key := fmt.Sprint(parameter["in"]) + ":" + fmt.Sprint(parameter["name"])
+			if i, ok := index[key]; ok {
+				parameters[i] = parameter
+				continue
+			}
+			index[key] = len(parameters)
+			parameters = append(parameters, parameter)
+		}
+	}
+	return parameters
+}
+
+// parameterValue returns the example value of a parameter, swagger 2
+// parameters hold their schema keywords themselves.
+func (s *OpenAPISpec) parameterValue(parameter map[string]interface{}) interface{} {
+	if value, ok := openAPIExample(parameter); ok {
+		return value
+	}
+	if schema, ok := parameter["schema"]; ok {
+		return s.exampleValue(schema, 0)
+	}
+	return s.exampleValue(parameter, 0)
+}
+
+// requestBodyValue returns the content type and example value of a request
+// body, json content is preferred over form content.
+func (s *OpenAPISpec) requestBodyValue(requestBody map[string]interface{}) (string, interface{}) {
+	content, _ := requestBody["content"].(map[string]interface{})
+	contentTypes := sortedKeys(content)
+	sort.SliceStable(contentTypes, func(i, j int) bool {
+		return openAPIContentTypeRank(contentTypes[i]) < openAPIContentTypeRank(contentTypes[j])
+	})
+	for _, contentType := range contentTypes {
+		if openAPIContentTypeRank(contentType) > 2 {
+			break
+		}
+		media, _ := content[contentType].(map[string]interface{})
+		if value, ok := openAPIExample(media); ok {
+			return contentType, value
+		}
+		return contentType, s.exampleValue(media["schema"], 0)
+	}
+	return "", nil
+}
+
+// openAPIContentTypeRank ranks the supported body content types
+func openAPIContentTypeRank(contentType string) int {
+	switch {
+	case strings.Contains(contentType, "json"):
+		return 0
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		return 1
+	case strings.Contains(contentType, "multipart/form-data"):
+		return 2
+	}
+	return 3
+}
+
+// exampleValue returns the example of a schema or a value derived from it
+func (s *OpenAPISpec) exampleValue(value interface{}, depth int) interface{} {
+	if depth > maxSchemaDepth {
+		return nil
+	}
+	schema, ok := s.resolve(value, 0).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if example, ok := openAPIExample(schema); ok {
+		return example
+	}
+	for _, keyword := range []string{"default", "const"} {
+		if value, ok := schema[keyword]; ok {
+			return value
+		}
+	}
+	if values, ok := schema["enum"].([]interface{}); ok && len(values) > 0 {
+		return values[0]
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if items, ok := schema[keyword].([]interface{}); ok && len(items) > 0 {
+			return s.exampleValue(items[0], depth+1)
+		}
+	}
+	if items, ok := schema["allOf"].([]interface{}); ok {
+		merged := make(map[string]interface{})
+		for _, item := range items {
+			if object, ok := s.exampleValue(item, depth+1).(map[string]interface{}); ok {
+				for key, value := range object {
+					merged[key] = value
+				}
+			}
+		}
+		return merged
+	}
+
+	format, _ := schema["format"].(string)
+	switch openAPIType(schema) {
+	case "object":
+		object := make(map[string]interface{})
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, property := range properties {
+			if propertyValue := s.exampleValue(property, depth+1); propertyValue != nil {
+				object[name] = propertyValue
+			}
+		}
+		return object
+	case "array":
+		if item := s.exampleValue(schema["items"], depth+1); item != nil {
+			return []interface{}{item}
+		}
+		return []interface{}{}
+	case "integer":
+		return 1
+	case "number":
+		return 1.5
+	case "boolean":
+		return true
+	}
+	switch format {
+	case "date":
+		return "2024-01-01"
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "email":
+		return FormData.Email
+	case "password":
+		return FormData.Password
+	case "uri", "url":
+		return "https://example.com"
+	case "ipv4":
+		return "127.0.0.1"
+	}
+	return FormData.Placeholder
+}
+
+// openAPIType returns the type of a schema, the first non null type of
+// OpenAPI 3.1 type arrays, or object if the schema has properties.
+func openAPIType(schema map[string]interface{}) string {
+	switch value := schema["type"].(type) {
+	case string:
+		return value
+	case []interface{}:
+		for _, item := range value {
+			if item != "null" {
+				return fmt.Sprint(item)
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+// openAPIExample returns the example or the first of the examples of an
+// object, OpenAPI 3 examples are objects holding the example value.
+func openAPIExample(object map[string]interface{}) (interface{}, bool) {
+	if value, ok := object["example"]; ok {
+		return value, true
+	}
+	switch examples := object["examples"].(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(examples) {
+			if example, ok := examples[key].(map[string]interface{}); ok {
+				if value, ok := example["value"]; ok {
+					return value, true
+				}
+			}
+		}
+	case []interface{}:
+		if len(examples) > 0 {
+			return examples[0], true
+		}
+	}
+	return nil, false
+}
+
+// resolve resolves local json pointer references of the specification
+func (s *OpenAPISpec) resolve(value interface{}, depth int) interface{} {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	ref, ok := object["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#/") || depth > maxSchemaDepth {
+		return value
+	}
+	var current interface{} = s.document
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		parent, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = parent[token]
+	}
+	return s.resolve(current, depth+1)
+}
+
+// encodeOpenAPIBody encodes a body value for its content type, returning the
+// content type to send which includes the boundary of multipart bodies.
+func encodeOpenAPIBody(value interface{}, contentType string) (string, string) {
+	switch {
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		values := url.Values{}
+		if object, ok := value.(map[string]interface{}); ok {
+			for key, item := range object {
+				values.Set(key, openAPIString(item))
+			}
+		}
+		return values.Encode(), contentType
+	case strings.Contains(contentType, "multipart/form-data"):
+		buffer := &bytes.Buffer{}
+		writer := multipart.NewWriter(buffer)
+		if object, ok := value.(map[string]interface{}); ok {
+			for _, key := range sortedKeys(object) {
+				_ = writer.WriteField(key, openAPIString(object[key]))
+			}
+		}
+		_ = writer.Close()
+		return buffer.String(), writer.FormDataContentType()
+	}
+	body, _ := jsoniter.ConfigCompatibleWithStandardLibrary.MarshalToString(value)
+	return body, contentType
+}
+
+// openAPIString returns the string form of a parameter value
+func openAPIString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, openAPIString(item))
+		}
+		return strings.Join(items, ",")
+	case map[string]interface{}:
+		text, _ := jsoniter.ConfigCompatibleWithStandardLibrary.MarshalToString(v)
+		return text
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// openAPIStrings returns the strings of a list value
+func openAPIStrings(value interface{}) []string {
+	items, _ := value.([]interface{})
+	var results []string
+	for _, item := range items {
+		if text, ok := item.(string); ok {
+			results = append(results, text)
+		}
+	}
+	return results
+}
+
+// sortedKeys returns the keys of the object in order for stable results
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIOperations(t *testing.T) {
+	t.Run("openapi 3", func(t *testing.T) {
+		spec, err := ParseOpenAPI(`{
+	"openapi": "3.0.3",
+	"servers": [{"url": "/{version}", "variables": {"version": {"default": "v1"}}}],
+	"paths": {
+		"/users/{id}": {
+			"parameters": [{"$ref": "#/components/parameters/id"}],
+			"get": {"parameters": [{"name": "fields", "in": "query", "schema": {"type": "array", "items": {"type": "string", "enum": ["name", "email"]}}}]},
+			"delete": {"parameters": [{"name": "X-Request-Id", "in": "header", "schema": {"type": "string", "format": "uuid"}}]}
+		},
+		"/users": {
+			"post": {"requestBody": {"content": {
+				"application/xml": {"schema": {"$ref": "#/components/schemas/User"}},
+				"application/json": {"schema": {"$ref": "#/components/schemas/User"}}
+			}}}
+		}
+	},
+	"components": {
+		"parameters": {"id": {"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "example": 42}}},
+		"schemas": {"User": {"type": "object", "properties": {"name": {"type": "string", "example": "jane"}, "admin": {"type": "boolean"}, "tags": {"type": "array", "items": {"type": "string", "default": "staff"}}}}}
+	}
+}`)
+		require.NoError(t, err)
+		require.False(t, spec.Swagger)
+
+		operations := spec.Operations("https://api.example.com/docs/openapi.json")
+		require.Len(t, operations, 3)
+
+		require.Equal(t, OpenAPIOperation{Method: http.MethodPost, URL: "https://api.example.com/v1/users", Path: "/users",
+			Body: `{"admin":true,"name":"jane","tags":["staff"]}`, Headers: map[string]string{"Content-Type": "application/json"}}, operations[0])
+		require.Equal(t, OpenAPIOperation{Method: http.MethodGet, URL: "https://api.example.com/v1/users/42?fields=name", Path: "/users/{id}", Headers: map[string]string{}}, operations[1])
+		require.Equal(t, http.MethodDelete, operations[2].Method)
+		require.Equal(t, "https://api.example.com/v1/users/42", operations[2].URL)
+		require.Equal(t, "3fa85f64-5717-4562-b3fc-2c963f66afa6", operations[2].Headers["X-Request-Id"])
+	})
+
+	t.Run("swagger 2", func(t *testing.T) {
+		spec, err := ParseOpenAPI(`swagger: "2.0"
+basePath: /api
+schemes: [https]
+paths:
+  /orders/{orderId}/items:
+    put:
+      consumes: [application/x-www-form-urlencoded]
+      parameters:
+        - {name: orderId, in: path, type: string}
+        - {name: sku, in: formData, type: string, example: AB-1}
+        - {name: quantity, in: formData, type: integer, minimum: 1}
+`)
+		require.NoError(t, err)
+		require.True(t, spec.Swagger)
+
+		operations := spec.Operations("http://shop.example.com/swagger.yaml")
+		require.Equal(t, []OpenAPIOperation{{
+			Method:  http.MethodPut,
+			URL:     "https://shop.example.com/api/orders/" + FormData.Placeholder + "/items",
+			Path:    "/orders/{orderId}/items",
+			Body:    "quantity=1&sku=AB-1",
+			Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		}}, operations)
+	})
+
+	t.Run("not a specification", func(t *testing.T) {
+		_, err := ParseOpenAPI(`{"swagger": "1.2", "paths": {}}`)
+		require.Error(t, err)
+		_, err = ParseOpenAPI(`{"name": "openapi"}`)
+		require.Error(t, err)
+	})
+}
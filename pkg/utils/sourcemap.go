@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"path"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/utils/errkit"
+)
+
+// SourceMap is a javascript or css source map (revision 3)
+type SourceMap struct {
+	Version        int                `json:"version"`
+	File           string             `json:"file"`
+	SourceRoot     string             `json:"sourceRoot"`
+	Sources        []string           `json:"sources"`
+	SourcesContent []*string          `json:"sourcesContent"`
+	Sections       []SourceMapSection `json:"sections"`
+}
+
+// SourceMapSection is a section of an index source map, it either embeds
+// a source map or references one by url.
+type SourceMapSection struct {
+	URL string     `json:"url"`
+	Map *SourceMap `json:"map"`
+}
+
+// SourceFile is an original source file referenced by a source map, its
+// content is empty if the source map does not embed it.
+type SourceFile struct {
+	Path    string
+	Content string
+}
+
+// ParseSourceMap parses a source map, skipping its optional xssi prefix
+func ParseSourceMap(data string) (*SourceMap, error) {
+	data = strings.TrimSpace(data)
+	if strings.HasPrefix(data, ")]}") {
+		if index := strings.IndexByte(data, '\n'); index != -1 {
+			data = data[index+1:]
+		}
+	}
+	if !strings.HasPrefix(data, "{") || (!strings.Contains(data, `"sources"`) && !strings.Contains(data, `"sections"`)) {
+		return nil, errkit.New("sourcemap: not a source map")
+	}
+	sourceMap := &SourceMap{}
+	if err := jsoniter.UnmarshalFromString(data, sourceMap); err != nil {
+		return nil, errkit.Wrap(err, "sourcemap: could not unmarshal source map")
+	}
+	if sourceMap.Version != 3 {
+		return nil, errkit.Newf("sourcemap: unsupported version %d", sourceMap.Version)
+	}
+	return sourceMap, nil
+}
+
+// Files returns the source files of the source map and of its embedded sections
+func (m *SourceMap) Files() []SourceFile {
+	var files []SourceFile
+	for i, source := range m.Sources {
+		if source == "" {
+			continue
+		}
+		file := SourceFile{Path: source}
+		if m.SourceRoot != "" && !strings.Contains(source, "://") && !strings.HasPrefix(source, "/") {
+			file.Path = strings.TrimSuffix(m.SourceRoot, "/") + "/" + source
+		}
+		if i < len(m.SourcesContent) && m.SourcesContent[i] != nil {
+			file.Content = *m.SourcesContent[i]
+		}
+		files = append(files, file)
+	}
+	for _, section := range m.Sections {
+		if section.Map != nil {
+			files = append(files, section.Map.Files()...)
+		}
+	}
+	return files
+}
+
+// SectionURLs returns the urls of the source maps referenced by the sections
+func (m *SourceMap) SectionURLs() []string {
+	var urls []string
+	for _, section := range m.Sections {
+		if section.URL != "" {
+			urls = append(urls, section.URL)
+		}
+		if section.Map != nil {
+			urls = append(urls, section.Map.SectionURLs()...)
+		}
+	}
+	return urls
+}
+
+// IsVendor returns true if the source file is a third party dependency or
+// a bundler runtime file.
+func (f SourceFile) IsVendor() bool {
+	return strings.Contains(f.Path, "node_modules/") || strings.Contains(f.Path, "bower_components/") || strings.Contains(f.Path, "webpack/bootstrap") || strings.Contains(f.Path, "(webpack)/")
+}
+
+// LocalPath returns a relative slash separated path for the source file,
+// bundler schemes, queries and parent directory segments are dropped.
+func (f SourceFile) LocalPath() string {
+	value := f.Path
+	if index := strings.Index(value, "://"); index != -1 {
+		value = value[index+len("://"):]
+	}
+	if index := strings.IndexAny(value, "?#"); index != -1 {
+		value = value[:index]
+	}
+	// cleaning the path as an absolute one drops the leading parent segments
+	return strings.TrimPrefix(path.Clean("/"+value), "/")
+}
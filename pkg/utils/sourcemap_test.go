@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSourceMap(t *testing.T) {
+	sourceMap, err := ParseSourceMap(`)]}'
+{"version":3,"sections":[{"offset":{"line":0,"column":0},"map":{"version":3,"sourceRoot":"app","sources":["main.ts","/abs/util.ts"],"sourcesContent":["export {}"],"mappings":""}},{"offset":{"line":1,"column":0},"url":"vendor.js.map"}]}`)
+	require.NoError(t, err)
+	require.Equal(t, []SourceFile{{Path: "app/main.ts", Content: "export {}"}, {Path: "/abs/util.ts"}}, sourceMap.Files())
+	require.Equal(t, []string{"vendor.js.map"}, sourceMap.SectionURLs())
+
+	_, err = ParseSourceMap(`{"version":2,"sources":[]}`)
+	require.Error(t, err)
+	_, err = ParseSourceMap(`{"name":"package"}`)
+	require.Error(t, err)
+
+	require.Equal(t, "src/api/client.js", SourceFile{Path: "webpack:///./src/api/client.js?a1b2"}.LocalPath())
+	require.Equal(t, "etc/passwd", SourceFile{Path: "../../../etc/passwd"}.LocalPath())
+	require.True(t, SourceFile{Path: "webpack:///./node_modules/react/index.js"}.IsVendor())
+}
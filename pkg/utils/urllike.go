@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// maxURLLikeLength is the maximum length of a value considered as url
+const maxURLLikeLength = 2048
+
+// absoluteURLRegex matches absolute http urls embedded in arbitrary text
+var absoluteURLRegex = regexp.MustCompile(`https?://[A-Za-z0-9_\-.]+(?::\d{1,5})?(?:/[^\s"'<>\\]*)?`)
+
+// IsURLLike returns true if the value looks like an absolute, protocol relative
+// or root relative url which can be requested by the crawler.
+func IsURLLike(value string) bool {
+	if value == "" || len(value) > maxURLLikeLength || strings.ContainsAny(value, " \t\r\n<>\"'{}") {
+		return false
+	}
+	lower := strings.ToLower(value)
+	switch {
+	case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"):
+		return IsURL(value)
+	case strings.HasPrefix(value, "//"):
+		return IsURL("https:" + value)
+	case strings.HasPrefix(value, "/"):
+		// a single slash or a path made only of slashes and dots is not useful
+		return strings.Trim(value, "/.") != ""
+	}
+	return false
+}
+
+// ExtractURLLikeValues returns the url-like values contained in the value.
+// JSON documents are walked recursively, otherwise the value itself is
+// checked and absolute urls embedded in it are extracted.
+func ExtractURLLikeValues(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	unique := make(map[string]struct{})
+	results := []string{}
+	add := func(item string) {
+		item = strings.TrimSpace(item)
+		if !IsURLLike(item) {
+			return
+		}
+		if _, ok := unique[item]; ok {
+			return
+		}
+		unique[item] = struct{}{}
+		results = append(results, item)
+	}
+
+	var extract func(item string)
+	extract = func(item string) {
+		if strings.HasPrefix(item, "{") || strings.HasPrefix(item, "[") {
+			var decoded interface{}
+			if err := jsoniter.UnmarshalFromString(item, &decoded); err == nil {
+				walkJSONStrings(decoded, extract)
+				return
+			}
+		}
+		if IsURLLike(item) {
+			add(item)
+			return
+		}
+		for _, match := range absoluteURLRegex.FindAllString(item, -1) {
+			add(strings.TrimRight(match, ".,;)"))
+		}
+	}
+	extract(value)
+	return results
+}
+
+// walkJSONStrings calls fn for each string contained in a decoded json value
+func walkJSONStrings(value interface{}, fn func(string)) {
+	switch v := value.(type) {
+	case string:
+		fn(strings.TrimSpace(v))
+	case []interface{}:
+		for _, item := range v {
+			walkJSONStrings(item, fn)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			walkJSONStrings(item, fn)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsURLLike(t *testing.T) {
+	valid := []string{
+		"https://api.example.com/v1",
+		"http://example.com",
+		"//cdn.example.com/app.js",
+		"/api/users",
+	}
+	for _, value := range valid {
+		require.True(t, IsURLLike(value), "could not detect url-like value %s", value)
+	}
+
+	invalid := []string{
+		"",
+		"/",
+		"./",
+		"true",
+		"eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig",
+		"/some path",
+		"api/users",
+		"ftp://example.com/file",
+	}
+	for _, value := range invalid {
+		require.False(t, IsURLLike(value), "invalid url-like value %s detected", value)
+	}
+}
+
+func TestExtractURLLikeValues(t *testing.T) {
+	t.Run("plain", func(t *testing.T) {
+		require.Equal(t, []string{"https://api.example.com/v1"}, ExtractURLLikeValues(" https://api.example.com/v1 "))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		value := `{"apiBase":"https://api.example.com/v2","flags":{"beta":true,"endpoint":"/internal/beta"},"list":["/a/b","nope","/a/b"]}`
+		require.ElementsMatch(t, []string{"https://api.example.com/v2", "/internal/beta", "/a/b"}, ExtractURLLikeValues(value))
+	})
+
+	t.Run("embedded", func(t *testing.T) {
+		value := "redirect=https://sso.example.com/login?next=1; theme=dark"
+		require.Equal(t, []string{"https://sso.example.com/login?next=1"}, ExtractURLLikeValues(value))
+	})
+
+	t.Run("none", func(t *testing.T) {
+		require.Empty(t, ExtractURLLikeValues("dark"))
+		require.Empty(t, ExtractURLLikeValues(`{"theme":"dark"}`))
+	})
+}
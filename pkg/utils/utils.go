@@ -30,24 +30,75 @@ func ParseSRCSetTag(value string) []string {
 }
 
 // ParseLinkTag parses link tag values returning found urls
-//
-// Inspired from: https://github.com/tomnomnom/linkheader
 func ParseLinkTag(value string) []string {
-	urls := make([]string, 0)
+	links := ParseLinkHeader(value)
+	urls := make([]string, 0, len(links))
+	for _, link := range links {
+		urls = append(urls, link.URL)
+	}
+	return urls
+}
 
-	for _, chunk := range strings.Split(value, ",") {
-		for _, piece := range strings.Split(chunk, ";") {
-			piece = strings.Trim(piece, " ")
-			if piece == "" {
-				continue
+// LinkHeaderValue is a link of a Link header with its parameters
+type LinkHeaderValue struct {
+	URL    string
+	Params map[string]string
+}
+
+// ParseLinkHeader parses the links of a Link header with their lowercased
+// parameters, commas and semicolons inside urls and quoted values are kept.
+func ParseLinkHeader(value string) []LinkHeaderValue {
+	var links []LinkHeaderValue
+	for {
+		start := strings.IndexByte(value, '<')
+		if start == -1 {
+			return links
+		}
+		end := strings.IndexByte(value[start:], '>')
+		if end == -1 {
+			return links
+		}
+		link := LinkHeaderValue{URL: strings.TrimSpace(value[start+1 : start+end]), Params: make(map[string]string)}
+		value = value[start+end+1:]
+
+		// parameters run until the comma separating the next link
+		for {
+			value = strings.TrimLeft(value, " \t")
+			if !strings.HasPrefix(value, ";") {
+				break
+			}
+			value = strings.TrimLeft(value[1:], " \t")
+			nameEnd := strings.IndexAny(value, "=;,")
+			if nameEnd == -1 {
+				nameEnd = len(value)
 			}
-			if piece[0] == '<' && piece[len(piece)-1] == '>' {
-				urls = append(urls, strings.Trim(piece, "<>"))
-				continue
+			name := strings.ToLower(strings.TrimSpace(value[:nameEnd]))
+			value = value[nameEnd:]
+			var paramValue string
+			if strings.HasPrefix(value, "=") {
+				value = strings.TrimLeft(value[1:], " \t")
+				if strings.HasPrefix(value, `"`) {
+					if quoteEnd := strings.IndexByte(value[1:], '"'); quoteEnd != -1 {
+						paramValue = value[1 : quoteEnd+1]
+						value = value[quoteEnd+2:]
+					} else {
+						paramValue, value = value[1:], ""
+					}
+				} else {
+					valueEnd := strings.IndexAny(value, ";,")
+					if valueEnd == -1 {
+						valueEnd = len(value)
+					}
+					paramValue = strings.TrimSpace(value[:valueEnd])
+					value = value[valueEnd:]
+				}
+			}
+			if _, ok := link.Params[name]; name != "" && !ok {
+				link.Params[name] = paramValue
 			}
 		}
+		links = append(links, link)
 	}
-	return urls
 }
 
 // ParseRefreshTag parses refresh tag values returning found urls
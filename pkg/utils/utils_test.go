@@ -14,6 +14,16 @@ func TestParseLinkTag(t *testing.T) {
 	require.ElementsMatch(t, []string{"https://api.github.com/user/58276/repos?page=2", "https://api.github.com/user/58276/repos?page=10"}, values, "could not parse correct links")
 }
 
+func TestParseLinkHeader(t *testing.T) {
+	header := `<https://example.com/de/>; rel="alternate"; hreflang="de", <https://example.com/feed?a=1,2>;rel=alternate;type=application/atom+xml;title="news; all"`
+
+	values := ParseLinkHeader(header)
+	require.Equal(t, []LinkHeaderValue{
+		{URL: "https://example.com/de/", Params: map[string]string{"rel": "alternate", "hreflang": "de"}},
+		{URL: "https://example.com/feed?a=1,2", Params: map[string]string{"rel": "alternate", "type": "application/atom+xml", "title": "news; all"}},
+	}, values)
+}
+
 func TestParseRefreshTag(t *testing.T) {
 	header := "999; url=/test/headers/refresh.found"
 